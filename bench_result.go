@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BenchResult is one benchmark run in machine-readable form (one CSV row or JSON line)
+type BenchResult struct {
+	Time       time.Time `json:"time"`
+	Graph      string    `json:"graph"`
+	Algorithm  string    `json:"algorithm"` // "cbfs" or "seq"
+	N          int       `json:"n"`
+	M          int       `json:"m"`
+	R          int       `json:"r"`
+	K          int       `json:"k"`
+	NS         int       `json:"ns"`
+	GOMAXPROCS int       `json:"gomaxprocs"`
+	// IterationMs[i]: time of iteration i over all batches; BatchMs[b]: mean time of batch b over the iterations
+	IterationMs []float64 `json:"iteration_ms"`
+	BatchMs     []float64 `json:"batch_ms"`
+	MedianMs    float64   `json:"median_ms"` // over iterations
+	MeanMs      float64   `json:"mean_ms"`
+	StddevMs    float64   `json:"stddev_ms"` // population standard deviation over iterations
+	MinMs       float64   `json:"min_ms"`
+	MaxMs       float64   `json:"max_ms"`
+	// PeakHeapBytes is the largest live heap (runtime.MemStats.HeapAlloc) seen between batches
+	PeakHeapBytes uint64 `json:"peak_heap_bytes"`
+}
+
+// heapSampler tracks the peak HeapAlloc; sample is called outside the timed regions
+type heapSampler struct {
+	peak uint64
+	ms   runtime.MemStats
+}
+
+func (h *heapSampler) sample() {
+	runtime.ReadMemStats(&h.ms)
+	h.peak = max(h.peak, h.ms.HeapAlloc)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// summarize fills the median / mean / stddev / min / max fields from IterationMs
+func (res *BenchResult) summarize() {
+	xs := slices.Clone(res.IterationMs)
+	if len(xs) == 0 {
+		return
+	}
+	slices.Sort(xs)
+	if len(xs)%2 == 1 {
+		res.MedianMs = xs[len(xs)/2]
+	} else {
+		res.MedianMs = (xs[len(xs)/2-1] + xs[len(xs)/2]) / 2
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	res.MeanMs = sum / float64(len(xs))
+	variance := 0.0
+	for _, x := range xs {
+		variance += (x - res.MeanMs) * (x - res.MeanMs)
+	}
+	res.StddevMs = math.Sqrt(variance / float64(len(xs)))
+	res.MinMs, res.MaxMs = xs[0], xs[len(xs)-1]
+}
+
+// graphName is the dataset name used in results: the file name without directory and extension
+func graphName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+var benchCSVHeader = []string{
+	"time", "graph", "algorithm", "n", "m", "r", "k", "ns", "gomaxprocs",
+	"iterations", "median_ms", "mean_ms", "stddev_ms", "min_ms", "max_ms", "peak_heap_bytes",
+	"iteration_ms", "batch_ms",
+}
+
+// csvRow renders res in the column order of benchCSVHeader; the time lists are ';'-separated
+func (res *BenchResult) csvRow() []string {
+	ms := func(x float64) string { return strconv.FormatFloat(x, 'f', 4, 64) }
+	list := func(xs []float64) string {
+		parts := make([]string, len(xs))
+		for i, x := range xs {
+			parts[i] = ms(x)
+		}
+		return strings.Join(parts, ";")
+	}
+	return []string{
+		res.Time.Format(time.RFC3339), res.Graph, res.Algorithm,
+		strconv.Itoa(res.N), strconv.Itoa(res.M), strconv.Itoa(res.R), strconv.Itoa(res.K), strconv.Itoa(res.NS),
+		strconv.Itoa(res.GOMAXPROCS), strconv.Itoa(len(res.IterationMs)),
+		ms(res.MedianMs), ms(res.MeanMs), ms(res.StddevMs), ms(res.MinMs), ms(res.MaxMs),
+		strconv.FormatUint(res.PeakHeapBytes, 10), list(res.IterationMs), list(res.BatchMs),
+	}
+}
+
+// resultFormat picks "csv" for .csv files and "json" (JSON lines) otherwise, unless format is given
+func resultFormat(path, format string) (string, error) {
+	switch format {
+	case "":
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			return "csv", nil
+		}
+		return "json", nil
+	case "csv", "json":
+		return format, nil
+	default:
+		return "", usageErrorf("unknown result format %q (want csv or json)", format)
+	}
+}
+
+// AppendBenchResults appends results to path as CSV rows (with a header when the file is new or empty)
+// or as JSON lines
+func AppendBenchResults(path, format string, results ...*BenchResult) (err error) {
+	format, err = resultFormat(path, format)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if format == "json" {
+		enc := json.NewEncoder(f)
+		for _, res := range results {
+			if err := enc.Encode(res); err != nil {
+				return fmt.Errorf("write %s: %w", path, err)
+			}
+		}
+		return nil
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		w.Write(benchCSVHeader)
+	}
+	for _, res := range results {
+		w.Write(res.csvRow())
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBenchResultSummary(t *testing.T) {
+	res := &BenchResult{IterationMs: []float64{4, 1, 3, 2}}
+	res.summarize()
+	if res.MedianMs != 2.5 || res.MeanMs != 2.5 || res.MinMs != 1 || res.MaxMs != 4 {
+		t.Fatalf("unexpected summary %+v", res)
+	}
+	if math.Abs(res.StddevMs-math.Sqrt(1.25)) > 1e-12 {
+		t.Fatalf("stddev %f, want %f", res.StddevMs, math.Sqrt(1.25))
+	}
+}
+
+func TestAppendBenchResults(t *testing.T) {
+	dir := t.TempDir()
+	res := &BenchResult{Graph: "grid", Algorithm: "cbfs", N: 4, M: 8, R: 2, K: 2, NS: 1, GOMAXPROCS: 1,
+		IterationMs: []float64{1.5, 2.5}, BatchMs: []float64{2}}
+	res.summarize()
+
+	// CSV: one header, then a row per result and append
+	path := filepath.Join(dir, "results.csv")
+	for i := 0; i < 2; i++ {
+		if err := AppendBenchResults(path, "", res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(f).ReadAll()
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "time" || rows[2][1] != "grid" || rows[2][len(rows[2])-2] != "1.5000;2.5000" {
+		t.Fatalf("unexpected CSV %v", rows)
+	}
+
+	// JSON lines
+	path = filepath.Join(dir, "results.jsonl")
+	if err := AppendBenchResults(path, "", res, res); err != nil {
+		t.Fatal(err)
+	}
+	f, err = os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	lines := 0
+	for sc.Scan() {
+		var got BenchResult
+		if err := json.Unmarshal(sc.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Graph != "grid" || got.MedianMs != 2 || len(got.BatchMs) != 1 {
+			t.Fatalf("unexpected JSON result %+v", got)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Fatalf("%d JSON lines, want 2", lines)
+	}
+	if err := AppendBenchResults(path, "xml", res); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,53 @@
+package bitutils
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetchOr(t *testing.T) {
+	var x uint64
+	done := make(chan struct{})
+	for i := 0; i < 64; i++ {
+		go func(bit int) {
+			FetchOr(&x, 1<<uint(bit))
+			done <- struct{}{}
+		}(i)
+	}
+	for i := 0; i < 64; i++ {
+		<-done
+	}
+	if x != ^uint64(0) {
+		t.Fatalf("x = %b, want all 64 bits", x)
+	}
+}
+
+// shared: every goroutine ORs into the same word (the EdgeFunc hot spot on high-degree vertices);
+// padded: each goroutine has its own cache line, for comparison
+func BenchmarkFetchOr(b *testing.B) {
+	b.Run("shared", func(b *testing.B) {
+		var x uint64
+		var id uint64
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			mask := uint64(1) << (atomic.AddUint64(&id, 1) % 64)
+			for pb.Next() {
+				FetchOr(&x, mask)
+				atomic.StoreUint64(&x, 0) // keep the CAS loop contended instead of a no-op OR
+			}
+		})
+	})
+	b.Run("padded", func(b *testing.B) {
+		words := make([][8]uint64, 1024)
+		var id uint64
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			w := &words[atomic.AddUint64(&id, 1)%uint64(len(words))][0]
+			for pb.Next() {
+				FetchOr(w, 1)
+				atomic.StoreUint64(w, 0)
+			}
+		})
+	})
+}
@@ -0,0 +1,4 @@
+// Package cbfspb holds the gRPC service definition of the server (cbfs.proto) and the code generated from it
+package cbfspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cbfs.proto
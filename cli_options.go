@@ -0,0 +1,252 @@
+package main
+
+import (
+	"cluster_bfs_go/graphutils"
+	"errors"
+	"flag"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// usageError is a bad command line (exit code 2); every other error is a failure (exit code 1)
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func isUsageError(err error) bool {
+	var ue *usageError
+	return errors.As(err, &ue)
+}
+
+// graphOptions are the flags shared by every command that loads a graph
+type graphOptions struct {
+	path  string
+	order string
+	lcc   bool
+	sym   bool
+	cores int
+	seed  uint64 // RNG seed of -order random
+}
+
+func (o *graphOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.path, "f", "", "path to the graph (.bin CSR, .txt/.adj adjacency text, or .bytepd)")
+	o.registerPreprocessing(fs)
+	fs.IntVar(&o.cores, "c", 20, "number of CPU cores to use (GOMAXPROCS)")
+}
+
+// registerPreprocessing registers only -order, -lcc and -sym, for commands that choose the graph and cores themselves
+func (o *graphOptions) registerPreprocessing(fs *flag.FlagSet) {
+	fs.StringVar(&o.order, "order", "", "relabel vertices before running: degree, bfs, random (seeded by -seed) or gorder")
+	fs.BoolVar(&o.lcc, "lcc", false, "run on the largest connected component only")
+	fs.BoolVar(&o.sym, "sym", false, "graph is symmetric: reuse G as its transpose instead of building GT")
+}
+
+// registerSeed registers -seed for -order random, for commands without seed selection (theirs sets seed)
+func (o *graphOptions) registerSeed(fs *flag.FlagSet) {
+	fs.Uint64Var(&o.seed, "seed", 0, "RNG seed of -order random (use the one printed by the run that built the index)")
+}
+
+// loadedGraph is the graph a command runs on, after the optional -lcc and -order steps
+type loadedGraph struct {
+	G, GT [][]int
+	// ToLocal[v]: ID of input vertex v in G (-1 if dropped), for mapping input IDs such as ground-truth pairs
+	ToLocal []int
+	// Identity is true when G still uses the input IDs (no -lcc or -order)
+	Identity bool
+}
+
+// M is the number of (directed) edges of G
+func (g *loadedGraph) M() int {
+	m := 0
+	for _, nbrs := range g.G {
+		m += len(nbrs)
+	}
+	return m
+}
+
+// load reads the graph and applies -c, -lcc, -order and -sym
+func (o *graphOptions) load() (*loadedGraph, error) {
+	if o.path == "" {
+		return nil, usageErrorf("missing -f graph file")
+	}
+	if o.cores > 0 {
+		runtime.GOMAXPROCS(o.cores)
+	}
+
+	// Read CSR and construct the graph
+	offs64, edges32, err := graphutils.ReadGraph(o.path)
+	if err != nil {
+		return nil, fmt.Errorf("loading graph: %w", err)
+	}
+	fmt.Printf("Graph %s: n=%d, m=%d\n", o.path, len(offs64)-1, len(edges32))
+	// Build Go adjacent lists
+	G := graphutils.BuildAdjFromCSR(offs64, edges32)
+	toLocal := make([]int, len(G))
+	for v := range toLocal {
+		toLocal[v] = v
+	}
+	// Optionally drop everything outside the largest connected component
+	if o.lcc {
+		var orig []int
+		offs64, edges32, orig = graphutils.LargestComponent(G)
+		G = graphutils.BuildAdjFromCSR(offs64, edges32)
+		for v := range toLocal {
+			toLocal[v] = -1
+		}
+		for i, v := range orig {
+			toLocal[v] = i
+		}
+		fmt.Printf("Largest component: n=%d, m=%d\n", len(G), len(edges32))
+	}
+	// Optionally relabel vertices for better cache locality
+	if o.order != "" {
+		var re *graphutils.Reordering
+		G, re, err = graphutils.Reorder(G, o.order, o.seed)
+		if err != nil {
+			return nil, usageErrorf("%v", err)
+		}
+		for v, l := range toLocal {
+			if l >= 0 {
+				toLocal[v] = re.NewID[l]
+			}
+		}
+		if o.order == "random" {
+			fmt.Printf("Reordered vertices by random (RNG seed %d)\n", o.seed)
+		} else {
+			fmt.Printf("Reordered vertices by %s\n", o.order)
+		}
+	}
+	return &loadedGraph{
+		G:        G,
+		GT:       graphutils.TransposeOrShare(G, o.sym),
+		ToLocal:  toLocal,
+		Identity: !o.lcc && o.order == "",
+	}, nil
+}
+
+// localSeeds maps seed batches from input IDs to IDs of G; the input sentinel len(ToLocal) becomes len(G)
+func (g *loadedGraph) localSeeds(seeds [][]int) ([][]int, error) {
+	out := make([][]int, len(seeds))
+	for b, batch := range seeds {
+		out[b] = make([]int, len(batch))
+		for i, v := range batch {
+			switch {
+			case v == len(g.ToLocal):
+				out[b][i] = len(g.G)
+			case g.ToLocal[v] < 0:
+				return nil, fmt.Errorf("batch %d: vertex %d is not in the graph after -lcc", b, v)
+			default:
+				out[b][i] = g.ToLocal[v]
+			}
+		}
+	}
+	return out, nil
+}
+
+// inputSeeds maps seed batches of G back to input IDs (the inverse of localSeeds)
+func (g *loadedGraph) inputSeeds(seeds [][]int) [][]int {
+	orig := make([]int, len(g.G)+1)
+	for v, l := range g.ToLocal {
+		if l >= 0 {
+			orig[l] = v
+		}
+	}
+	orig[len(g.G)] = len(g.ToLocal)
+	out := make([][]int, len(seeds))
+	for b, batch := range seeds {
+		out[b] = graphutils.MapVertices(batch, orig)
+	}
+	return out
+}
+
+// seedOptions are the flags shared by every command that needs seed batches
+type seedOptions struct {
+	ns, k    int
+	seed     int64
+	file     string
+	save     string
+	strategy string
+	disjoint bool
+	mincc    int
+}
+
+func (o *seedOptions) register(fs *flag.FlagSet) {
+	fs.IntVar(&o.ns, "ns", 10, "number of seed batches")
+	fs.IntVar(&o.k, "k", 64, "seeds per batch")
+	o.registerSelection(fs)
+}
+
+// registerSelection registers the seed flags except -ns and -k, for commands that set the batch shape themselves
+func (o *seedOptions) registerSelection(fs *flag.FlagSet) {
+	fs.Int64Var(&o.seed, "seed", -1, "RNG seed for seed selection (-1: pick one and print it)")
+	fs.StringVar(&o.file, "seeds", "", "read seed batches from this file instead of selecting them (.json or text)")
+	fs.StringVar(&o.save, "saveseeds", "", "write the seed batches used to this file (.json or text)")
+	fs.StringVar(&o.strategy, "strategy", "1hop", "seed selection strategy: "+strings.Join(graphutils.SeedStrategyNames(), ", "))
+	fs.BoolVar(&o.disjoint, "disjoint", false, "never reuse a vertex as a seed in two batches")
+	fs.IntVar(&o.mincc, "mincc", -1, "never pick seeds in components smaller than this (0: outside the largest component, -1: off)")
+}
+
+// rngSeed resolves -seed, picking one when it is not set; the caller passes it on to graphOptions,
+// so -order random and the seed selection replay together
+func (o *seedOptions) rngSeed() uint64 {
+	if o.seed < 0 {
+		o.seed = int64(graphutils.RandomSeed() >> 1)
+	}
+	return uint64(o.seed)
+}
+
+// batches selects seed batches on g (or loads them from -seeds) and saves them if -saveseeds is set
+// R is passed to strategies that depend on the radius. Seed files use the input IDs, so they do not
+// depend on -lcc / -order and can be shared with the C++ runs
+func (o *seedOptions) batches(g *loadedGraph, R int) ([][]int, error) {
+	var seeds [][]int
+	if o.file != "" {
+		var err error
+		seeds, err = graphutils.ReadSeeds(o.file)
+		if err == nil {
+			err = graphutils.ValidateSeeds(seeds, len(g.ToLocal))
+		}
+		if err == nil {
+			seeds, err = g.localSeeds(seeds)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading seeds: %w", err)
+		}
+		seeds = graphutils.TrimSentinel(seeds, len(g.G))
+		fmt.Printf("Loaded %d seed batches from %s\n", len(seeds), o.file)
+	} else {
+		seed := o.rngSeed()
+		fmt.Printf("Seed selection RNG seed: %d (replay with -seed %d)\n", seed, seed)
+		opts := graphutils.SeedOptions{Seed: seed, Disjoint: o.disjoint, R: R}
+		if o.mincc >= 0 {
+			opts.Mark = graphutils.MarkSmallComponents(g.G, o.mincc)
+		}
+		selector, err := graphutils.NewSeedSelector(o.strategy, opts)
+		if err != nil {
+			return nil, usageErrorf("%v", err)
+		}
+		sb, err := selector.Select(g.G, o.ns, o.k)
+		if err != nil {
+			return nil, usageErrorf("%v", err)
+		}
+		seeds = sb.Used()
+		fmt.Printf("%s: %d seeds in %d batches (%d padded slots, %d unused batches)\n",
+			selector.Name(), sb.Actual, len(seeds), sb.Padding, sb.Unused)
+	}
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("no seed batch to run")
+	}
+	if o.save != "" {
+		if err := graphutils.WriteSeeds(o.save, g.inputSeeds(seeds)); err != nil {
+			return nil, fmt.Errorf("saving seeds: %w", err)
+		}
+	}
+	return seeds, nil
+}
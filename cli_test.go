@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"cluster_bfs_go/graphutils"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestCLIExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	graph := filepath.Join(dir, "grid.bin")
+	offs, edges := graphutils.Grid2D(8, 8)
+	if err := graphutils.WriteGraphToBin(graph, offs, edges); err != nil {
+		t.Fatal(err)
+	}
+	index := filepath.Join(dir, "grid.idx")
+	catalog := filepath.Join(dir, "catalog.txt")
+	missing := filepath.Join(dir, "missing.txt")
+	if err := os.WriteFile(catalog, []byte("# test\nGRID "+graph+"\nNONE "+filepath.Join(dir, "none.bin")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(missing, []byte("NONE "+filepath.Join(dir, "none.bin")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{nil, exitUsage},
+		{[]string{"help"}, exitOK},
+		{[]string{"help", "query"}, exitOK},
+		{[]string{"nosuchcommand"}, exitUsage},
+		{[]string{"stats", "-h"}, exitOK},
+		{[]string{"stats", "-nosuchflag"}, exitUsage},
+		{[]string{"stats"}, exitUsage},
+		{[]string{"stats", "-f", filepath.Join(dir, "missing.bin")}, exitFailure},
+		{[]string{"stats", "-f", graph, "extra"}, exitUsage},
+		{[]string{"stats", "-f", graph, "-c", "2"}, exitOK},
+		{[]string{"-f", graph, "-sym", "-k", "4", "-ns", "2", "-t", "1", "-seed", "1", "-c", "2"}, exitOK},
+		{[]string{"verify", "-f", graph, "-sym", "-k", "4", "-ns", "2", "-ligra=false", "-seed", "1", "-c", "2"}, exitOK},
+		{[]string{"build-index", "-f", graph, "-sym", "-k", "4", "-ns", "2", "-seed", "1", "-c", "2"}, exitUsage},
+		{[]string{"build-index", "-f", graph, "-sym", "-lcc", "-k", "4", "-ns", "2", "-seed", "1", "-c", "2", "-o", index}, exitOK},
+		{[]string{"query", "-index", index, "-u", "0", "-v", "63"}, exitOK},
+		{[]string{"query", "-index", index, "-u", "0"}, exitUsage},
+		{[]string{"query", "-index", index, "-u", "0", "-v", "64"}, exitFailure},
+		{[]string{"serve", "-f", graph}, exitUsage},
+		{[]string{"serve", "-f", graph, "-index", index, "-addr", "0.0.0.0:0"}, exitUsage},
+		{[]string{"serve", "-f", graph, "-index", index, "-grpc", "0.0.0.0:9090"}, exitUsage},
+		{[]string{"serve", "-f", graph, "-sym", "-index", filepath.Join(dir, "missing.idx"), "-addr", "127.0.0.1:0"}, exitFailure},
+		{[]string{"serve", "-f", graph, "-sym", "-lcc", "-index", index, "-addr", "127.0.0.1:0", "-timeout", "200ms"}, exitOK},
+		{[]string{"-f", graph, "-sym", "-k", "4", "-ns", "2", "-t", "1", "-seed", "1", "-timeout", "1ns"}, exitFailure},
+		{[]string{"convert", "-f", graph, "-o", filepath.Join(dir, "grid.txt")}, exitOK},
+		{[]string{"stats", "-f", graph, "-cpuprofile", filepath.Join(dir, "cpu.pprof"), "-memprofile", filepath.Join(dir, "mem.pprof"),
+			"-trace", filepath.Join(dir, "run.trace"), "-blockprofile", filepath.Join(dir, "block.pprof")}, exitOK},
+		{[]string{"stats", "-f", graph, "-cpuprofile", filepath.Join(dir, "no", "such", "dir.pprof")}, exitFailure},
+		{[]string{"sweep", "-catalog", catalog, "-sym", "-r", "1,2", "-k", "2,4", "-c", "1,2", "-ns", "2", "-t", "1", "-seed", "1"}, exitOK},
+		{[]string{"sweep", "-catalog", catalog, "-k", "2,x"}, exitUsage},
+		{[]string{"sweep", "-catalog", catalog, "-seeds", missing}, exitUsage},
+		{[]string{"sweep", "-catalog", missing}, exitFailure},
+	} {
+		if got := runCLI(tc.args); got != tc.want {
+			t.Errorf("%v: exit code %d, want %d", tc.args, got, tc.want)
+		}
+	}
+	for _, name := range []string{"cpu.pprof", "mem.pprof", "run.trace", "block.pprof"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Errorf("profile %s missing or empty (%v)", name, err)
+		}
+	}
+}
+
+// -order random relabels the same way for the same -seed
+func TestCLIOrderRandomReplays(t *testing.T) {
+	dir := t.TempDir()
+	graph := filepath.Join(dir, "grid.bin")
+	offs, edges := graphutils.Grid2D(8, 8)
+	if err := graphutils.WriteGraphToBin(graph, offs, edges); err != nil {
+		t.Fatal(err)
+	}
+	var outs [][]byte
+	for i := 0; i < 2; i++ {
+		out := filepath.Join(dir, fmt.Sprintf("random%d.txt", i))
+		if got := runCLI([]string{"convert", "-f", graph, "-order", "random", "-seed", "3", "-o", out}); got != exitOK {
+			t.Fatalf("convert: exit code %d", got)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outs = append(outs, data)
+	}
+	if !bytes.Equal(outs[0], outs[1]) {
+		t.Fatal("-order random -seed 3 relabeled differently on two runs")
+	}
+}
+
+// Seed files hold input IDs: seeds saved after -lcc -order replay unchanged without them
+func TestCLISeedFilesUseInputIDs(t *testing.T) {
+	dir := t.TempDir()
+	graph := filepath.Join(dir, "grid.bin")
+	offs, edges := graphutils.Grid2D(8, 8)
+	if err := graphutils.WriteGraphToBin(graph, offs, edges); err != nil {
+		t.Fatal(err)
+	}
+	saved, replayed := filepath.Join(dir, "saved.txt"), filepath.Join(dir, "replayed.txt")
+	for _, args := range [][]string{
+		{"verify", "-f", graph, "-sym", "-lcc", "-order", "degree", "-k", "4", "-ns", "2", "-ligra=false", "-seed", "1", "-saveseeds", saved},
+		{"verify", "-f", graph, "-sym", "-k", "4", "-ns", "2", "-ligra=false", "-seeds", saved, "-saveseeds", replayed},
+	} {
+		if got := runCLI(args); got != exitOK {
+			t.Fatalf("%v: exit code %d", args, got)
+		}
+	}
+	a, errA := graphutils.ReadSeeds(saved)
+	b, errB := graphutils.ReadSeeds(replayed)
+	if errA != nil || errB != nil || !reflect.DeepEqual(a, b) {
+		t.Fatalf("seeds changed between the runs: %v and %v (%v, %v)", a, b, errA, errB)
+	}
+	// 1hop batches are a center and its neighbors, in the input graph too
+	G := graphutils.BuildAdjFromCSR(offs, edges)
+	for _, batch := range a {
+		for _, v := range batch[1:] {
+			if v != batch[0] && !slices.Contains(G[batch[0]], v) {
+				t.Fatalf("batch %v: %d is not a neighbor of the center in the input graph", batch, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package client is a Go client for the distance server started by "cluster_bfs_go serve"
+// It also defines the JSON messages of the HTTP API, which the server uses as well
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DistanceResponse answers GET /distance?u=&v=
+// Distance is nil when the index has no path between u and v (no batch covers both) and the BiBFS fallback found none;
+// Exact is true when Distance is the shortest-path distance (certified by the labels or found by the BiBFS),
+// false when it is only the index's upper bound
+type DistanceResponse struct {
+	U        int     `json:"u"`
+	V        int     `json:"v"`
+	Distance *uint64 `json:"distance"`
+	Exact    bool    `json:"exact"`
+}
+
+// BatchRequest is the body of POST /distance/batch
+type BatchRequest struct {
+	Pairs [][2]int `json:"pairs"`
+}
+
+// BatchResponse answers POST /distance/batch; Distances[i] and Exact[i] belong to Pairs[i] (nil: unreachable or unknown vertex)
+type BatchResponse struct {
+	Distances []*uint64 `json:"distances"`
+	Exact     []bool    `json:"exact"`
+}
+
+// Neighbor is a vertex and its hop distance from the query vertex
+type Neighbor struct {
+	V int `json:"v"`
+	D int `json:"d"`
+}
+
+// NeighborsResponse answers GET /neighbors-within?v=&r=: every vertex within r hops of v, in BFS order
+type NeighborsResponse struct {
+	V         int        `json:"v"`
+	R         int        `json:"r"`
+	Neighbors []Neighbor `json:"neighbors"`
+}
+
+// HealthResponse answers GET /healthz
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// Metadata answers GET /metadata
+type Metadata struct {
+	Graph         string  `json:"graph"`
+	Index         string  `json:"index"`
+	N             int     `json:"n"`
+	M             int     `json:"m"`
+	R             int     `json:"r"`
+	Batches       int     `json:"batches"`
+	K             int     `json:"k"`
+	MaxRadius     int     `json:"max_radius"` // largest r accepted by /neighbors-within (and by gRPC jobs)
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+// ErrorResponse is the body of every non-2xx response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Error is returned for non-2xx responses
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("server returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Client talks to one server
+type Client struct {
+	BaseURL string       // e.g. "http://127.0.0.1:8080"
+	HTTP    *http.Client // http.DefaultClient when nil
+}
+
+// New returns a client for the server at baseURL
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// do sends the request and decodes a JSON response into out
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var e ErrorResponse
+		body, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(body, &e) != nil || e.Error == "" {
+			e.Error = strings.TrimSpace(string(body))
+		}
+		return &Error{StatusCode: resp.StatusCode, Message: e.Error}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+// Distance returns the distance estimate between u and v (nil if the index has no path)
+func (c *Client) Distance(ctx context.Context, u, v int) (*uint64, error) {
+	var resp DistanceResponse
+	q := url.Values{"u": {strconv.Itoa(u)}, "v": {strconv.Itoa(v)}}
+	if err := c.get(ctx, "/distance", q, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Distance, nil
+}
+
+// Distances answers many pairs in one request
+func (c *Client) Distances(ctx context.Context, pairs [][2]int) ([]*uint64, error) {
+	body, err := json.Marshal(BatchRequest{Pairs: pairs})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/distance/batch", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var resp BatchResponse
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Distances) != len(pairs) {
+		return nil, fmt.Errorf("got %d distances for %d pairs", len(resp.Distances), len(pairs))
+	}
+	return resp.Distances, nil
+}
+
+// NeighborsWithin returns the vertices within r hops of v with their hop distances
+func (c *Client) NeighborsWithin(ctx context.Context, v, r int) ([]Neighbor, error) {
+	var resp NeighborsResponse
+	q := url.Values{"v": {strconv.Itoa(v)}, "r": {strconv.Itoa(r)}}
+	if err := c.get(ctx, "/neighbors-within", q, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Neighbors, nil
+}
+
+// Health checks that the server is up
+func (c *Client) Health(ctx context.Context) error {
+	var resp HealthResponse
+	if err := c.get(ctx, "/healthz", nil, &resp); err != nil {
+		return err
+	}
+	if resp.Status != "ok" {
+		return fmt.Errorf("server status %q", resp.Status)
+	}
+	return nil
+}
+
+// Metadata describes the graph and index being served
+func (c *Client) Metadata(ctx context.Context) (*Metadata, error) {
+	var resp Metadata
+	if err := c.get(ctx, "/metadata", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
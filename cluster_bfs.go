@@ -3,9 +3,9 @@ package main
 /*
 #cgo CXXFLAGS: -std=c++17
 #cgo CXXFLAGS: -I${SRCDIR}/cwrapper
-#cgo CXXFLAGS: -I${SRCDIR}/vendor/ligra
-#cgo CXXFLAGS: -I${SRCDIR}/vendor/parlay
-#cgo CXXFLAGS: -I${SRCDIR}/vendor/src
+#cgo CXXFLAGS: -I${SRCDIR}/third_party/ligra
+#cgo CXXFLAGS: -I${SRCDIR}/third_party/parlay
+#cgo CXXFLAGS: -I${SRCDIR}/third_party/src
 #cgo CXXFLAGS: -Wno-integer-overflow
 #cgo CXXFLAGS: -Wno-shift-count-overflow
 #cgo LDFLAGS: -lm
@@ -19,7 +19,9 @@ import "C" // To apply C++ Ligra code to Go for verification
 import (
 	"cluster_bfs_go/bitutils"
 	"cluster_bfs_go/graphutils"
+	"context"
 	"fmt"
+	"math/bits"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -37,6 +39,7 @@ type ClusterBFS struct {
 	R         int // Input
 	INF       uint64
 	round     uint64
+	seeds     []int // seeds of the current batch; bit j of S[v][r] is seeds[j]
 }
 
 // Initialize member attributes
@@ -72,12 +75,14 @@ func (cbfs *ClusterBFS) Init(vertices []int) []int {
 	// Initialize the seed vertices (i.e., the starting points of BFS)
 	seeds := []int{}
 	for i, v := range vertices {
-		if i != 0 && v == vertices[0] {
+		// stop at the padding (a repeat of the first seed) or at the sentinel n of an unused batch
+		if (i != 0 && v == vertices[0]) || v >= n {
 			break
 		}
 		cbfs.S1[v] = 1 << uint(i)
 		seeds = append(seeds, v)
 	}
+	cbfs.seeds = seeds
 	return seeds
 }
 
@@ -134,6 +139,12 @@ func (cbfs *ClusterBFS) CondFunc(v int, round uint64) bool {
 
 // Test BFS within a single cluster
 func (cbfs *ClusterBFS) RunCBFS(seeds []int) {
+	cbfs.RunCBFSContext(context.Background(), seeds) // never cancelled
+}
+
+// RunCBFSContext is RunCBFS with cancellation: ctx is checked between frontiers and by the EdgeMap workers.
+// When it is done, D and S hold the rounds completed so far and the error is a *PartialResultError
+func (cbfs *ClusterBFS) RunCBFSContext(ctx context.Context, seeds []int) error {
 	// Initializes the initial frontiers of the BFS (cluster) from seeds
 	frontier := NewEmptySparse()
 	frontier.AddVertices(seeds)
@@ -159,17 +170,62 @@ func (cbfs *ClusterBFS) RunCBFS(seeds []int) {
 	total := 0
 	// Inner loop for BFS within the current frontiers
 	for frontier.Size() > 0 {
+		if err := ctx.Err(); err != nil {
+			return &PartialResultError{Op: "ClusterBFS", Unit: "rounds", Completed: int(cbfs.round), Err: err}
+		}
 		frontier.Apply(cbfs.FrontierFunc) // Update our output
 		cbfs.round++
 		m := frontier.Size()
 		total += m
-		frontier = frontierMap.Run(frontier, false) // Update the next level frontiers
+		// Update the next level frontiers; a cancelled EdgeMap leaves S1 half-updated, but D and S
+		// only change in FrontierFunc, so the rounds already recorded stay valid
+		var err error
+		if frontier, err = frontierMap.RunContext(ctx, frontier, false); err != nil {
+			return &PartialResultError{Op: "ClusterBFS", Unit: "rounds", Completed: int(cbfs.round), Err: ctx.Err()}
+		}
+	}
+	return nil
+}
+
+// DistanceFromSeed returns the distance from the j-th seed of the batch to v
+// If seed j reached v within the R-round window, the distance D[v]+r is exact (exact == true).
+// Otherwise seed j first arrives after the window, so D[v]+R is a lower bound (exact == false).
+// Returns INF if no seed of the batch reached v.
+func (cbfs *ClusterBFS) DistanceFromSeed(j, v int) (d uint64, exact bool) {
+	if cbfs.D[v] == cbfs.INF {
+		return cbfs.INF, false
+	}
+	// r is the first relative round whose bitmask contains seed j
+	for r := 0; r < cbfs.R; r++ {
+		if cbfs.S[v][r]&(1<<uint(j)) != 0 {
+			return cbfs.D[v] + uint64(r), true
+		}
 	}
+	return cbfs.D[v] + uint64(cbfs.R), false
+}
+
+// SeedsAt returns the seeds (vertex IDs) of the batch whose distance to v is exactly d
+// Only distances within the window D[v] … D[v]+R−1 are recorded, so any other d yields no seeds
+func (cbfs *ClusterBFS) SeedsAt(v int, d uint64) []int {
+	if cbfs.D[v] == cbfs.INF || d < cbfs.D[v] || d-cbfs.D[v] >= uint64(cbfs.R) {
+		return nil
+	}
+	var out []int
+	for mask := cbfs.S[v][d-cbfs.D[v]]; mask != 0; mask &= mask - 1 {
+		out = append(out, cbfs.seeds[bits.TrailingZeros64(mask)])
+	}
+	return out
 }
 
 // VerifyCBFS: mimics the C++ verify_CBFS logic, using Ligra’s BFS via cgo
 // seeds: the list of seed vertices (cbfs.Init returned these).
 func (cbfs *ClusterBFS) VerifyCBFS(seeds []int) error {
+	return cbfs.VerifyCBFSContext(context.Background(), seeds)
+}
+
+// VerifyCBFSContext is VerifyCBFS with cancellation, checked between seeds (a Ligra BFS cannot be interrupted);
+// a *PartialResultError counts the seeds that passed
+func (cbfs *ClusterBFS) VerifyCBFSContext(ctx context.Context, seeds []int) error {
 	n := len(cbfs.G)
 	if len(seeds) == 0 {
 		return fmt.Errorf("no seeds provided")
@@ -177,7 +233,10 @@ func (cbfs *ClusterBFS) VerifyCBFS(seeds []int) error {
 
 	// 1) Flatten G and GT into CSR form
 	offsGo, edgesGo := graphutils.FlattenCSR(cbfs.G)
-	offsGT, edgesGT := graphutils.FlattenCSR(cbfs.GT)
+	offsGT, edgesGT := offsGo, edgesGo
+	if n == 0 || len(cbfs.GT) == 0 || &cbfs.GT[0] != &cbfs.G[0] { // G doubles as GT for symmetric graphs
+		offsGT, edgesGT = graphutils.FlattenCSR(cbfs.GT)
+	}
 
 	// 2) allocate C-backed arrays
 	offsC := make([]C.int, len(offsGo))
@@ -219,6 +278,9 @@ func (cbfs *ClusterBFS) VerifyCBFS(seeds []int) error {
 		if j != 0 && seed == seeds[0] {
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return &PartialResultError{Op: "VerifyCBFS", Unit: "seeds", Completed: j, Err: err}
+		}
 
 		// prepare output buffer
 		answer := make([]uint64, n)
@@ -231,24 +293,13 @@ func (cbfs *ClusterBFS) VerifyCBFS(seeds []int) error {
 		// compare Ligra’s distances (answer) vs. your bit-parallel result
 		for v := 0; v < n; v++ {
 			dTrue := answer[v]
-			dQuery := cbfs.D[v]
 			if dTrue == ligraInf32 {
 				// unreachable in true BFS, skip
 				continue
 			}
-			// reconstruct the extra rounds from cbfs.S[v]
-			var sum uint64
-			changed := false
-			for r := 0; r < R; r++ {
-				sum |= cbfs.S[v][r]
-				if sum&(1<<uint(j)) != 0 {
-					dQuery += uint64(r)
-					changed = true
-					break
-				}
-			}
+			dQuery, exact := cbfs.DistanceFromSeed(j, v)
 			// mismatch checks
-			if changed {
+			if exact {
 				if dQuery != dTrue {
 					return fmt.Errorf(
 						"seed %d, vertex %d: true=%d, ours=%d",
@@ -256,11 +307,12 @@ func (cbfs *ClusterBFS) VerifyCBFS(seeds []int) error {
 					)
 				}
 			} else {
-				// allow up to ((R+1)/2)*2 slack
-				if dTrue-dQuery > uint64((R+1)/2)*2 {
+				// allow up to ((R+1)/2)*2 slack past D[v], like verify_CBFS
+				// (measured from D[v], not from the lower bound D[v]+R returned by DistanceFromSeed)
+				if dTrue-cbfs.D[v] > uint64((R+1)/2)*2 {
 					return fmt.Errorf(
-						"seed %d, vertex %d out of range: true=%d, ours=%d",
-						seed, v, dTrue, dQuery,
+						"seed %d, vertex %d out of range: true=%d, D=%d",
+						seed, v, dTrue, cbfs.D[v],
 					)
 				}
 			}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"cluster_bfs_go/graphutils"
+	"testing"
+)
+
+// decodeFuzzInput turns arbitrary bytes into a small directed graph, a batch of distinct seeds and a radius
+/*
+byte 0: n = 1 + b%48
+byte 1: R = 1 + b%6
+byte 2: k = 1 + b%min(n, 64)
+next k bytes: seeds (mod n, duplicates skipped)
+remaining byte pairs: edges u→v (mod n; self-loops and multi-edges kept)
+*/
+func decodeFuzzInput(data []byte) (G [][]int, batch []int, R int, ok bool) {
+	if len(data) < 3 {
+		return nil, nil, 0, false
+	}
+	n := 1 + int(data[0])%48
+	R = 1 + int(data[1])%6
+	k := 1 + int(data[2])%min(n, 64)
+	data = data[3:]
+
+	taken := make([]bool, n)
+	for len(batch) < k && len(data) > 0 {
+		v := int(data[0]) % n
+		data = data[1:]
+		if !taken[v] {
+			taken[v] = true
+			batch = append(batch, v)
+		}
+	}
+	if len(batch) == 0 {
+		return nil, nil, 0, false
+	}
+
+	G = make([][]int, n)
+	for ; len(data) >= 2; data = data[2:] {
+		u, v := int(data[0])%n, int(data[1])%n
+		G[u] = append(G[u], v)
+	}
+	return G, batch, R, true
+}
+
+// FuzzClusterBFS checks ClusterBFS against the sequential references on fuzzer-generated graphs
+// go test -run '^$' -fuzz '^FuzzClusterBFS$' -fuzztime 30s
+func FuzzClusterBFS(f *testing.F) {
+	f.Add([]byte{5, 1, 1, 0, 0, 1, 1, 2, 2, 3, 3, 4})
+	f.Add([]byte{9, 2, 3, 0, 4, 8, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 8, 8, 0})
+	f.Add([]byte{16, 3, 63, 1, 2, 3, 4, 5, 0, 1, 1, 0, 0, 0, 2, 7, 7, 2, 3, 9, 9, 3, 15, 15})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		G, batch, R, ok := decodeFuzzInput(data)
+		if !ok {
+			t.Skip()
+		}
+		checkAgainstSequential(t, "fuzz", G, graphutils.TransposeAdj(G), batch, R)
+	})
+}
+
+// FuzzClusterBFSSymmetric runs the same check with every edge in both directions and G doubling as GT
+func FuzzClusterBFSSymmetric(f *testing.F) {
+	f.Add([]byte{12, 2, 4, 0, 3, 6, 9, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		G, batch, R, ok := decodeFuzzInput(data)
+		if !ok {
+			t.Skip()
+		}
+		var pairs [][2]int
+		for u, nbrs := range G {
+			for _, v := range nbrs {
+				pairs = append(pairs, [2]int{u, v})
+			}
+		}
+		H := graphutils.BuildAdjFromCSR(graphutils.SymmetricCSR(len(G), pairs))
+		checkAgainstSequential(t, "fuzz-sym", H, H, batch, R)
+	})
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"cluster_bfs_go/graphutils"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//go:embed data/test.txt
+var toyGraphText string
+
+type testGraph struct {
+	name  string
+	G, GT [][]int
+}
+
+// testGraphs are small embedded and generated graphs, so the suite needs no input files
+func testGraphs(t *testing.T) []testGraph {
+	offs, edges, err := graphutils.ReadAdjText(strings.NewReader(toyGraphText))
+	if err != nil {
+		t.Fatal(err)
+	}
+	toy := graphutils.BuildAdjFromCSR(offs, edges)
+	graphs := []testGraph{{name: "data/test.txt", G: toy, GT: graphutils.TransposeAdj(toy)}}
+	for _, g := range []struct {
+		name  string
+		build func() ([]uint64, []uint32)
+	}{
+		{"path", func() ([]uint64, []uint32) { return graphutils.PathGraph(20) }},
+		{"cycle", func() ([]uint64, []uint32) { return graphutils.CycleGraph(15) }},
+		{"star", func() ([]uint64, []uint32) { return graphutils.StarGraph(10) }},
+		{"grid2d", func() ([]uint64, []uint32) { return graphutils.Grid2D(6, 7) }},
+		{"grid3d", func() ([]uint64, []uint32) { return graphutils.Grid3D(3, 3, 4) }},
+		{"erdos-renyi", func() ([]uint64, []uint32) { return graphutils.ErdosRenyi(300, 600, 5) }},
+		{"kronecker", func() ([]uint64, []uint32) { return graphutils.Kronecker(8, 4, 5) }},
+		{"barabasi-albert", func() ([]uint64, []uint32) { return graphutils.BarabasiAlbert(300, 2, 5) }},
+	} {
+		G := graphutils.BuildAdjFromCSR(g.build())
+		graphs = append(graphs, testGraph{name: g.name, G: G, GT: G})
+	}
+	return graphs
+}
+
+// seqDistance is the distance from seed si in SequentialBFS output (its last, i.e. best, arrival at v)
+func seqDistance(S []Sentry, si int) (int, bool) {
+	d, ok := 0, false
+	for _, e := range S {
+		if e.Seed == si && (!ok || e.Dist < d) {
+			d, ok = e.Dist, true
+		}
+	}
+	return d, ok
+}
+
+// checkAgainstSequential runs ClusterBFS on one batch and compares it with both sequential references:
+// D matches SequentialBFS, the labels match SequentialClusterBFS, and DistanceFromSeed is exact or a lower bound
+func checkAgainstSequential(t *testing.T, name string, G, GT [][]int, batch []int, R int) {
+	t.Helper()
+	n := len(G)
+	Dseq, Sseq := SequentialBFS(G, batch)
+	cbfs := &ClusterBFS{G: G, GT: GT, R: R}
+	cbfs.RunCBFS(cbfs.Init(batch))
+	Dref, Sref := SequentialClusterBFS(G, batch, R)
+	for v := 0; v < n; v++ {
+		if cbfs.D[v] == cbfs.INF {
+			if Dseq[v] != 1_000_000_000 {
+				t.Fatalf("%s k=%d R=%d v=%d: unreached, seq=%d", name, len(batch), R, v, Dseq[v])
+			}
+		} else if int(cbfs.D[v]) != Dseq[v] {
+			t.Fatalf("%s k=%d R=%d v=%d: D=%d, seq=%d", name, len(batch), R, v, cbfs.D[v], Dseq[v])
+		}
+		if cbfs.D[v] != Dref[v] || !slices.Equal(cbfs.S[v], Sref[v]) {
+			t.Fatalf("%s k=%d R=%d v=%d: labels D=%d S=%b, SequentialClusterBFS D=%d S=%b",
+				name, len(batch), R, v, cbfs.D[v], cbfs.S[v], Dref[v], Sref[v])
+		}
+		for j := range batch {
+			want, reached := seqDistance(Sseq[v], j)
+			d, exact := cbfs.DistanceFromSeed(j, v)
+			switch {
+			case !reached:
+				// only a lower bound (D[v]+R, or INF) can be reported
+				if exact {
+					t.Fatalf("%s k=%d R=%d: seed %d cannot reach %d, got exact %d", name, len(batch), R, j, v, d)
+				}
+			case exact && d != uint64(want), !exact && d > uint64(want):
+				t.Fatalf("%s k=%d R=%d: seed %d to %d: got %d (exact=%v), true %d", name, len(batch), R, j, v, d, exact, want)
+			}
+		}
+	}
+}
+
+// ClusterBFS against both sequential references for many R and k
+func TestClusterMatchesSequentialSuite(t *testing.T) {
+	for _, g := range testGraphs(t) {
+		n := len(g.G)
+		for _, k := range []int{1, 2, 7, 64} {
+			rng := rand.New(rand.NewPCG(uint64(n), uint64(k)))
+			batch := rng.Perm(n)[:min(k, n)]
+			for _, R := range []int{1, 2, 3, 5} {
+				checkAgainstSequential(t, g.name, g.G, g.GT, batch, R)
+			}
+		}
+	}
+}
+
+// On a path the distance from seed j to v is |v - seed|: exact inside the window, a lower bound outside
+func TestDistanceFromSeedOnPath(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.PathGraph(12))
+	batch := []int{5, 4, 6}
+	for _, R := range []int{1, 3, 12} {
+		cbfs := &ClusterBFS{G: G, GT: G, R: R}
+		cbfs.RunCBFS(cbfs.Init(batch))
+		for v := range G {
+			for j, seed := range batch {
+				want := uint64(abs(v - seed))
+				d, exact := cbfs.DistanceFromSeed(j, v)
+				if exact && d != want || !exact && d > want {
+					t.Fatalf("R=%d seed %d, vertex %d: got %d (exact=%v), true %d", R, seed, v, d, exact, want)
+				}
+				if R == 12 && !exact {
+					t.Fatalf("R=%d seed %d, vertex %d: expected an exact distance", R, seed, v)
+				}
+				if exact && !slices.Contains(cbfs.SeedsAt(v, d), seed) {
+					t.Fatalf("R=%d: SeedsAt(%d, %d) = %v misses seed %d", R, v, d, cbfs.SeedsAt(v, d), seed)
+				}
+			}
+		}
+	}
+	cbfs := &ClusterBFS{G: G, GT: G, R: 3}
+	cbfs.RunCBFS(cbfs.Init(batch))
+	if got := cbfs.SeedsAt(0, 4); !slices.Equal(got, []int{4}) {
+		t.Fatalf("SeedsAt(0, 4) = %v, want [4]", got)
+	}
+	if got := cbfs.SeedsAt(0, 1); got != nil {
+		t.Fatalf("SeedsAt(0, 1) = %v, want none", got)
+	}
+}
+
+// A seed that arrives after the window may be at most ((R+1)/2)*2 rounds past D[v]
+func TestVerifyCBFSSlack(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.PathGraph(20))
+	cbfs := &ClusterBFS{G: G, GT: G, R: 2}
+	cbfs.RunCBFS(cbfs.Init([]int{0}))
+	if err := cbfs.VerifyCBFS([]int{0}); err != nil {
+		t.Fatal(err)
+	}
+	// relabel vertex 10 (true distance 10) as if the seed arrived after the window
+	cbfs.S[10] = make([]uint64, cbfs.R)
+	for D, ok := range map[uint64]bool{8: true, 7: false} { // 7: off by R+1, within R of D[v]+R
+		cbfs.D[10] = D
+		if err := cbfs.VerifyCBFS([]int{0}); (err == nil) != ok {
+			t.Fatalf("D[10]=%d: VerifyCBFS returned %v", D, err)
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// benchGraph is a generated Kronecker graph shared by the benchmarks (built once)
+var benchGraph = sync.OnceValue(func() [][]int {
+	return graphutils.BuildAdjFromCSR(graphutils.Kronecker(14, 16, 1))
+})
+
+// benchBatch is a batch of k seeds: the center and its neighbors, like SelectSeeds1
+func benchBatch(G [][]int, k int) []int {
+	center := graphutils.OrderByDegrees(G)[0]
+	batch := []int{center}
+	for _, v := range G[center] {
+		if len(batch) == k {
+			break
+		}
+		batch = append(batch, v)
+	}
+	return batch
+}
+
+func BenchmarkClusterBFSInit(b *testing.B) {
+	G := benchGraph()
+	batch := benchBatch(G, 64)
+	cbfs := &ClusterBFS{G: G, GT: G, R: 2}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cbfs.Init(batch)
+	}
+}
+
+func BenchmarkRunCBFS(b *testing.B) {
+	G := benchGraph()
+	for _, R := range []int{1, 2, 4} {
+		for _, k := range []int{8, 64} {
+			b.Run(fmt.Sprintf("R=%d/k=%d", R, k), func(b *testing.B) {
+				batch := benchBatch(G, k)
+				cbfs := &ClusterBFS{G: G, GT: G, R: R}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					seeds := cbfs.Init(batch)
+					b.StartTimer()
+					cbfs.RunCBFS(seeds)
+				}
+			})
+		}
+	}
+}
+
+// A cancelled context stops every traversal with a *PartialResultError that unwraps to the context's error
+func TestCancelledContext(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.Grid2D(20, 20))
+	batch := []int{0, 210, 399}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	check := func(op string, err error, completed int) {
+		t.Helper()
+		var partial *PartialResultError
+		if !errors.As(err, &partial) || partial.Op != op || partial.Completed != completed || !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: got %v, want a %s error after %d", op, err, op, completed)
+		}
+	}
+
+	cbfs := &ClusterBFS{G: G, GT: G, R: 2}
+	seeds := cbfs.Init(batch)
+	check("ClusterBFS", cbfs.RunCBFSContext(ctx, seeds), 0)
+	for v, d := range cbfs.D {
+		if d != cbfs.INF {
+			t.Fatalf("vertex %d labeled in round %d by a run cancelled before its first round", v, d)
+		}
+	}
+	check("VerifyCBFS", cbfs.VerifyCBFSContext(ctx, batch), 0)
+	_, _, err := SequentialBFSContext(ctx, G, batch)
+	check("SequentialBFS", err, 1)
+	o, err := BuildOracleContext(ctx, G, G, [][]int{batch}, 2)
+	check("BuildOracle", err, 0)
+	if len(o.D) != 0 {
+		t.Fatalf("cancelled BuildOracle kept %d batches", len(o.D))
+	}
+
+	em := NewEdgeMap(G, G, func(u, v int, e int, backwards bool) bool { return true }, func(v int) bool { return true }, Identity[int])
+	for _, vs := range []VertexSubset{NewSparse([]int{0}), NewDense(denseOf(batch, len(G)))} {
+		out, err := em.RunContext(ctx, vs, false)
+		check("EdgeMap", err, out.Size())
+	}
+
+	// the same run without a deadline completes
+	if err := cbfs.RunCBFSContext(context.Background(), cbfs.Init(batch)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A deadline that expires mid-run stops ClusterBFS between or inside rounds; the rounds it reports
+// as completed are labeled exactly as by the sequential reference, and nothing after them is
+func TestCancelledMidRun(t *testing.T) {
+	// a long path: thousands of cheap rounds, so the deadline lands mid-run rather than in the setup
+	G := graphutils.BuildAdjFromCSR(graphutils.PathGraph(5000))
+	batch := []int{0, 1, 3}
+	R := 2
+	cbfs := &ClusterBFS{G: G, GT: G, R: R}
+	seeds := cbfs.Init(batch)
+	start := time.Now()
+	if err := cbfs.RunCBFSContext(context.Background(), seeds); err != nil {
+		t.Fatal(err)
+	}
+	full, rounds := time.Since(start), int(cbfs.round)
+
+	seeds = cbfs.Init(batch)
+	ctx, cancel := context.WithTimeout(context.Background(), full/2)
+	defer cancel()
+	start = time.Now()
+	err := cbfs.RunCBFSContext(ctx, seeds)
+	elapsed := time.Since(start)
+	var partial *PartialResultError
+	if !errors.As(err, &partial) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a ClusterBFS error after the deadline", err)
+	}
+	if partial.Completed == 0 || partial.Completed >= rounds {
+		t.Fatalf("completed %d of %d rounds, want a run cut short after its first round", partial.Completed, rounds)
+	}
+	if elapsed >= full {
+		t.Fatalf("cancelled run took %v, the full run %v", elapsed, full)
+	}
+
+	D, S := SequentialClusterBFS(G, batch, R)
+	done := uint64(partial.Completed)
+	for v := range G {
+		if D[v] >= done {
+			if cbfs.D[v] != cbfs.INF {
+				t.Fatalf("vertex %d labeled in round %d, after the %d completed rounds", v, cbfs.D[v], done)
+			}
+			continue
+		}
+		if cbfs.D[v] != D[v] {
+			t.Fatalf("vertex %d: D = %d, want %d", v, cbfs.D[v], D[v])
+		}
+		for r := 0; r < R; r++ {
+			want := S[v][r]
+			if D[v]+uint64(r) >= done {
+				want = 0
+			}
+			if cbfs.S[v][r] != want {
+				t.Fatalf("vertex %d: S[%d] = %b, want %b", v, r, cbfs.S[v][r], want)
+			}
+		}
+	}
+}
+
+// Cancelling while EdgeMap runs stops its workers: most of the edges are never visited
+func TestEdgeMapCancelledMidRun(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.Kronecker(15, 16, 2))
+	GT := graphutils.TransposeAdj(G)
+	m := 0
+	for _, nbrs := range G {
+		m += len(nbrs)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int64
+	em := NewEdgeMap(G, GT, func(u, v int, e int, backwards bool) bool {
+		if calls.Add(1) == 1 {
+			cancel()
+		}
+		return true
+	}, func(v int) bool { return true }, Identity[int])
+	all := make([]bool, len(G))
+	for v := range all {
+		all[v] = true
+	}
+	out, err := em.RunContext(ctx, NewDense(all), false)
+	var partial *PartialResultError
+	if !errors.As(err, &partial) || !errors.Is(err, context.Canceled) || partial.Completed != out.Size() {
+		t.Fatalf("got %v, want an EdgeMap error counting the %d vertices found", err, out.Size())
+	}
+	if c := calls.Load(); c > int64(m/2) {
+		t.Fatalf("%d of %d edges visited after cancelling on the first one", c, m)
+	}
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bufio"
+	"cluster_bfs_go/graphutils"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// command is one subcommand of the CLI: setup registers its flags on fs and returns the action to run after parsing
+// The action stops early once ctx is done (-timeout or Ctrl-C)
+type command struct {
+	name    string
+	summary string
+	setup   func(fs *flag.FlagSet) func(ctx context.Context) error
+}
+
+var commands = []command{
+	{"bench", "time ClusterBFS (or the sequential BFS) over all seed batches", benchCommand},
+	{"verify", "check ClusterBFS against the sequential reference and Ligra's BFS", verifyCommand},
+	{"build-index", "build the distance oracle from the seed batches and save it", buildIndexCommand},
+	{"query", "answer distance queries from a saved index", queryCommand},
+	{"stats", "print graph statistics", statsCommand},
+	{"convert", "convert a graph between formats (optionally after -lcc / -order)", convertCommand},
+	{"eval", "report oracle accuracy against ground-truth distances", evalCommand},
+	{"sweep", "bench ClusterBFS and the sequential BFS over a catalog of graphs and a parameter grid", sweepCommand},
+	{"serve", "answer distance queries from a saved index over HTTP on localhost", serveCommand},
+}
+
+// bench: the original single-batch benchmark
+func benchCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	var s seedOptions
+	g.register(fs)
+	s.register(fs)
+	t := fs.Int("t", 3, "number of iterations")
+	r := fs.Int("r", 2, "BFS radius")
+	verify := fs.Bool("v", false, "verify the first batch with Ligra BFS")
+	seq := fs.Bool("seq", false, "run the sequential BFS instead of ClusterBFS")
+	out := fs.String("out", "", "append the result to this file (CSV for .csv files, JSON lines otherwise)")
+	format := fs.String("format", "", "result format for -out: csv or json (default: from the file extension)")
+	name := fs.String("name", "", "graph name recorded in the result (default: the file name of -f)")
+	return func(ctx context.Context) error {
+		if *t < 1 || *r < 1 {
+			return usageErrorf("-t and -r must be positive")
+		}
+		if _, err := resultFormat(*out, *format); err != nil {
+			return err
+		}
+		g.seed = s.rngSeed()
+		lg, err := g.load()
+		if err != nil {
+			return err
+		}
+		seeds, err := s.batches(lg, *r)
+		if err != nil {
+			return err
+		}
+		res, err := singleBatchTest(ctx, seeds, lg.G, lg.GT, *t, *verify, *r, *seq)
+		if err != nil {
+			return err
+		}
+		res.Graph, res.M = graphName(g.path), lg.M()
+		if *name != "" {
+			res.Graph = *name
+		}
+		fmt.Printf("median %.3f ms, stddev %.3f ms, peak heap %d MiB\n", res.MedianMs, res.StddevMs, res.PeakHeapBytes>>20)
+		if *out != "" {
+			return AppendBenchResults(*out, *format, res)
+		}
+		return nil
+	}
+}
+
+// verify: labels of every checked batch must match SequentialClusterBFS, and distances must pass VerifyCBFS
+func verifyCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	var s seedOptions
+	g.register(fs)
+	s.register(fs)
+	r := fs.Int("r", 2, "BFS radius")
+	count := fs.Int("batches", 1, "number of batches to verify (0: all)")
+	ligra := fs.Bool("ligra", true, "also compare distances with Ligra's BFS (one BFS per seed)")
+	return func(ctx context.Context) error {
+		if *r < 1 || *count < 0 {
+			return usageErrorf("-r must be positive and -batches non-negative")
+		}
+		g.seed = s.rngSeed()
+		lg, err := g.load()
+		if err != nil {
+			return err
+		}
+		seeds, err := s.batches(lg, *r)
+		if err != nil {
+			return err
+		}
+		if *count > 0 && *count < len(seeds) {
+			seeds = seeds[:*count]
+		}
+		cbfs := &ClusterBFS{G: lg.G, GT: lg.GT, R: *r}
+		for i, batch := range seeds {
+			if err := cbfs.RunCBFSContext(ctx, cbfs.Init(batch)); err != nil {
+				return fmt.Errorf("batch %d: %w", i, err)
+			}
+			D, S := SequentialClusterBFS(lg.G, batch, *r)
+			for v := range lg.G {
+				if D[v] != cbfs.D[v] || !slices.Equal(S[v], cbfs.S[v]) {
+					return fmt.Errorf("batch %d, vertex %d: labels differ from the sequential reference", i, v)
+				}
+			}
+			if *ligra {
+				if err := cbfs.VerifyCBFSContext(ctx, batch); err != nil {
+					return fmt.Errorf("batch %d: %w", i, err)
+				}
+			}
+			fmt.Printf("batch %d: PASS\n", i)
+		}
+		fmt.Printf("PASS correctness check on %d batches!\n", len(seeds))
+		return nil
+	}
+}
+
+// build-index: BuildOracle + SaveOracle
+func buildIndexCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	var s seedOptions
+	g.register(fs)
+	s.register(fs)
+	r := fs.Int("r", 2, "BFS radius of the labels")
+	out := fs.String("o", "", "write the index to this file (required)")
+	return func(ctx context.Context) error {
+		if *out == "" {
+			return usageErrorf("missing -o index file")
+		}
+		if *r < 1 {
+			return usageErrorf("-r must be positive")
+		}
+		g.seed = s.rngSeed()
+		lg, err := g.load()
+		if err != nil {
+			return err
+		}
+		if !graphutils.IsSymmetric(lg.G) {
+			return errNotSymmetric
+		}
+		seeds, err := s.batches(lg, *r)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		oracle, err := BuildOracleContext(ctx, lg.G, lg.GT, seeds, *r)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Oracle built in %v\n", time.Since(start))
+		if !lg.Identity {
+			oracle.ToLocal = lg.ToLocal
+		}
+		if err := SaveOracle(*out, oracle); err != nil {
+			return err
+		}
+		fmt.Printf("Index (n=%d, R=%d, %d batches) written to %s\n", len(lg.G), *r, len(seeds), *out)
+		return nil
+	}
+}
+
+// formatDistance prints INF as "inf"
+func formatDistance(d, inf uint64) string {
+	if d == inf {
+		return "inf"
+	}
+	return strconv.FormatUint(d, 10)
+}
+
+// query: one pair from -u/-v, or "u v" lines from -pairs; vertex IDs are those of the input graph
+func queryCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	index := fs.String("index", "", "index file written by build-index (required)")
+	u := fs.Int("u", -1, "source vertex")
+	v := fs.Int("v", -1, "target vertex")
+	pairs := fs.String("pairs", "", `file of "u v" lines to query ("-": stdin); prints "u v d"`)
+	return func(ctx context.Context) error {
+		if *index == "" {
+			return usageErrorf("missing -index file")
+		}
+		if (*pairs == "") == (*u < 0 || *v < 0) {
+			return usageErrorf("give either -u and -v, or -pairs")
+		}
+		oracle, err := LoadOracle(*index)
+		if err != nil {
+			return err
+		}
+		// query maps input IDs to the labels; vertices outside the index are unreachable
+		query := func(a, b int) uint64 {
+			la, okA := oracle.Local(a)
+			lb, okB := oracle.Local(b)
+			if !okA || !okB {
+				return oracle.INF
+			}
+			return oracle.Query(la, lb)
+		}
+		if *pairs == "" {
+			if _, ok := oracle.Local(*u); !ok {
+				return fmt.Errorf("vertex %d is not in the index", *u)
+			}
+			if _, ok := oracle.Local(*v); !ok {
+				return fmt.Errorf("vertex %d is not in the index", *v)
+			}
+			fmt.Println(formatDistance(query(*u, *v), oracle.INF))
+			return nil
+		}
+
+		var in io.Reader = os.Stdin
+		if *pairs != "-" {
+			f, err := os.Open(*pairs)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		sc := bufio.NewScanner(in)
+		w := bufio.NewWriter(os.Stdout)
+		defer w.Flush()
+		for line := 1; sc.Scan(); line++ {
+			fields := strings.Fields(sc.Text())
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			if len(fields) < 2 {
+				return fmt.Errorf("%s:%d: want \"u v\"", *pairs, line)
+			}
+			a, errA := strconv.Atoi(fields[0])
+			b, errB := strconv.Atoi(fields[1])
+			if errA != nil || errB != nil {
+				return fmt.Errorf("%s:%d: bad vertex pair %q", *pairs, line, sc.Text())
+			}
+			fmt.Fprintf(w, "%d %d %s\n", a, b, formatDistance(query(a, b), oracle.INF))
+		}
+		return sc.Err()
+	}
+}
+
+// stats: size, degrees, symmetry and connectivity of the graph
+func statsCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	g.register(fs)
+	g.registerSeed(fs)
+	return func(ctx context.Context) error {
+		lg, err := g.load()
+		if err != nil {
+			return err
+		}
+		G := lg.G
+		n, m := len(G), 0
+		minDeg, maxDeg, isolated := -1, 0, 0
+		for _, nbrs := range G {
+			d := len(nbrs)
+			m += d
+			if minDeg < 0 || d < minDeg {
+				minDeg = d
+			}
+			maxDeg = max(maxDeg, d)
+			if d == 0 {
+				isolated++
+			}
+		}
+		labels := graphutils.ConnectedComponents(G)
+		_, largest := graphutils.LargestComponentLabel(labels)
+		components := 0
+		for _, size := range graphutils.ComponentSizes(labels) {
+			if size > 0 {
+				components++
+			}
+		}
+		fmt.Printf("vertices:   %d\n", n)
+		fmt.Printf("edges:      %d\n", m)
+		fmt.Printf("degree:     min %d, max %d, avg %.2f\n", max(minDeg, 0), maxDeg, float64(m)/float64(max(n, 1)))
+		fmt.Printf("isolated:   %d\n", isolated)
+		fmt.Printf("symmetric:  %v\n", graphutils.IsSymmetric(G))
+		fmt.Printf("components: %d (largest: %d vertices, %.2f%%)\n", components, largest, 100*float64(largest)/float64(max(n, 1)))
+		return nil
+	}
+}
+
+// convert: load with the shared graph options and write the result (.txt/.adj text, or CSR binary)
+func convertCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	g.register(fs)
+	g.registerSeed(fs)
+	out := fs.String("o", "", "output file (required; .txt/.adj for adjacency text, anything else for the CSR binary)")
+	return func(ctx context.Context) error {
+		if *out == "" {
+			return usageErrorf("missing -o output file")
+		}
+		lg, err := g.load()
+		if err != nil {
+			return err
+		}
+		offs, edges := graphutils.FlattenCSR(lg.G)
+		offs64 := make([]uint64, len(offs))
+		for i, o := range offs {
+			offs64[i] = uint64(o)
+		}
+		edges32 := make([]uint32, len(edges))
+		for i, e := range edges {
+			edges32[i] = uint32(e)
+		}
+		if err := graphutils.WriteGraph(*out, offs64, edges32); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote n=%d, m=%d to %s\n", len(lg.G), len(edges32), *out)
+		return nil
+	}
+}
+
+// eval: build the oracle (or load it with -index) and compare it with ground-truth distances
+func evalCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	var s seedOptions
+	g.register(fs)
+	s.register(fs)
+	r := fs.Int("r", 2, "BFS radius of the labels")
+	gt := fs.String("gt", "", "ground-truth distance file (required)")
+	index := fs.String("index", "", "evaluate this saved index instead of building one from -f")
+	return func(ctx context.Context) error {
+		if *gt == "" {
+			return usageErrorf("missing -gt ground-truth file")
+		}
+		truth, err := graphutils.ReadGroundTruth(*gt)
+		if err != nil {
+			return fmt.Errorf("loading ground truth: %w", err)
+		}
+
+		var oracle *Oracle
+		name := s.strategy
+		if *index != "" {
+			if oracle, err = LoadOracle(*index); err != nil {
+				return err
+			}
+			name = *index
+		} else {
+			if *r < 1 {
+				return usageErrorf("-r must be positive")
+			}
+			g.seed = s.rngSeed()
+			lg, err := g.load()
+			if err != nil {
+				return err
+			}
+			seeds, err := s.batches(lg, *r)
+			if err != nil {
+				return err
+			}
+			start := time.Now()
+			if oracle, err = BuildOracleContext(ctx, lg.G, lg.GT, seeds, *r); err != nil {
+				return err
+			}
+			fmt.Printf("Oracle built in %v\n", time.Since(start))
+			if !lg.Identity {
+				oracle.ToLocal = lg.ToLocal
+			}
+		}
+
+		// keep the pairs whose endpoints are both in the labeled graph, renamed to its IDs
+		local := truth[:0]
+		for _, p := range truth {
+			u, okU := oracle.Local(p.U)
+			v, okV := oracle.Local(p.V)
+			if okU && okV {
+				local = append(local, graphutils.DistancePair{U: u, V: v, D: p.D})
+			}
+		}
+		rep := EvaluateOracle(oracle, local)
+		fmt.Printf("Ground truth (%s): %d pairs, %d covered, %d exact (%.2f%%), mean stretch %.4f, max stretch %.2f\n",
+			name, rep.Pairs, rep.Covered, rep.Exact, 100*float64(rep.Exact)/float64(max(rep.Pairs, 1)), rep.MeanStretch, rep.MaxStretch)
+		return nil
+	}
+}
@@ -1,8 +1,8 @@
 #include "wrapper.h"
-#include <vendor/parlay/sequence.h>
-#include <vendor/parlay/primitives.h>
-#include <vendor/src/ligra_light.h>
-#include <vendor/src/BFS_ligra.h>
+#include <third_party/parlay/sequence.h>
+#include <third_party/parlay/primitives.h>
+#include <third_party/src/ligra_light.h>
+#include <third_party/src/BFS_ligra.h>
 #include <array>
 #include <atomic>
 #include <vector>
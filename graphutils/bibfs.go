@@ -0,0 +1,48 @@
+package graphutils
+
+// BiBFS returns d(s, t) by a bidirectional BFS: forward from s on G and backward from t on GT,
+// one full level at a time on the side with the smaller frontier. Like query_BiBFS in ADO_cluster.h
+// it gives up once more than limit vertices have been visited (limit ≤ 0: no limit);
+// ok is false when it gave up or when t is not reachable from s
+func BiBFS(G, GT [][]int, s, t, limit int) (d int, ok bool) {
+	if s == t {
+		return 0, true
+	}
+	adj := [2][][]int{G, GT}
+	dist := [2]map[int]int{{s: 0}, {t: 0}}
+	frontier := [2][]int{{s}, {t}}
+	level := [2]int{}
+	visited := 2
+	for len(frontier[0]) > 0 && len(frontier[1]) > 0 {
+		side := 0
+		if len(frontier[1]) < len(frontier[0]) {
+			side = 1
+		}
+		other := 1 - side
+		level[side]++
+		// the best meeting point of this level is a shortest path: any shorter one would have met earlier
+		best := -1
+		var next []int
+		for _, u := range frontier[side] {
+			for _, x := range adj[side][u] {
+				if _, seen := dist[side][x]; seen {
+					continue
+				}
+				dist[side][x] = level[side]
+				next = append(next, x)
+				visited++
+				if dx, met := dist[other][x]; met && (best < 0 || level[side]+dx < best) {
+					best = level[side] + dx
+				}
+				if limit > 0 && visited > limit && best < 0 {
+					return 0, false
+				}
+			}
+		}
+		if best >= 0 {
+			return best, true
+		}
+		frontier[side] = next
+	}
+	return 0, false
+}
@@ -0,0 +1,41 @@
+package graphutils
+
+import "testing"
+
+// BiBFS must agree with a plain BFS on every pair, and give up when the search budget is too small
+func TestBiBFS(t *testing.T) {
+	G := BuildAdjFromCSR(Grid2D(4, 7))
+	for s := range G {
+		dist := map[int]int{s: 0}
+		frontier := []int{s}
+		for len(frontier) > 0 {
+			var next []int
+			for _, u := range frontier {
+				for _, x := range G[u] {
+					if _, ok := dist[x]; !ok {
+						dist[x] = dist[u] + 1
+						next = append(next, x)
+					}
+				}
+			}
+			frontier = next
+		}
+		for v := range G {
+			if d, ok := BiBFS(G, G, s, v, 0); !ok || d != dist[v] {
+				t.Fatalf("BiBFS(%d, %d) = %d, %v; want %d", s, v, d, ok, dist[v])
+			}
+		}
+	}
+	if _, ok := BiBFS(G, G, 0, len(G)-1, 6); ok {
+		t.Fatal("expected BiBFS to give up with a budget of 6 vertices")
+	}
+
+	// directed path 0 → 1 → 2: no way back
+	D := [][]int{{1}, {2}, {}}
+	if d, ok := BiBFS(D, TransposeAdj(D), 0, 2, 0); !ok || d != 2 {
+		t.Fatalf("BiBFS(0, 2) = %d, %v", d, ok)
+	}
+	if _, ok := BiBFS(D, TransposeAdj(D), 2, 0, 0); ok {
+		t.Fatal("0 is not reachable from 2")
+	}
+}
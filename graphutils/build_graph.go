@@ -1,48 +1,116 @@
 package graphutils
 
+import (
+	"cluster_bfs_go/parlay_go"
+	"slices"
+	"sort"
+	"sync/atomic"
+)
+
 // BuildAdjFromCSR: turns CSR into an adjacency‐list [][]int
+// All lists share one backing array (like the CSR itself) and are filled in parallel
 func BuildAdjFromCSR(offsets []uint64, edges []uint32) [][]int {
 	n := len(offsets) - 1
 	G := make([][]int, n)
-	for u := 0; u < n; u++ {
-		for idx := offsets[u]; idx < offsets[u+1]; idx++ {
-			G[u] = append(G[u], int(edges[idx]))
+	backing := make([]int, len(edges))
+	parlay_go.ParallelFor(n, func(u int) {
+		lo, hi := offsets[u], offsets[u+1]
+		for idx := lo; idx < hi; idx++ {
+			backing[idx] = int(edges[idx])
 		}
-	}
+		// cap the capacity so an append on G[u] can never overwrite G[u+1]
+		G[u] = backing[lo:hi:hi]
+	})
 	return G
 }
 
 // TransposeAdj: transposes G (adjacent list) to GT
+// Counting sort by destination: atomic in-degree counts, a prefix sum for the offsets, then a parallel scatter.
+// Each GT[v] is sorted afterwards so the result matches the sequential construction (sources in increasing order).
 func TransposeAdj(G [][]int) [][]int {
 	n := len(G)
-	GT := make([][]int, n)
-	for u, nbrs := range G {
-		for _, v := range nbrs {
-			GT[v] = append(GT[v], u)
+	// 1) in-degree of every vertex
+	deg := make([]int64, n)
+	parlay_go.ParallelFor(n, func(u int) {
+		for _, v := range G[u] {
+			atomic.AddInt64(&deg[v], 1)
 		}
-	}
+	})
+	// 2) offsets of every GT[v] inside one backing array
+	offs, m := parlay_go.Scan(deg)
+	// 3) scatter: every edge (u, v) claims the next free slot of v
+	next := make([]int64, n)
+	copy(next, offs)
+	backing := make([]int, m)
+	parlay_go.ParallelFor(n, func(u int) {
+		for _, v := range G[u] {
+			backing[atomic.AddInt64(&next[v], 1)-1] = u
+		}
+	})
+	// 4) slice out and sort every list
+	GT := make([][]int, n)
+	parlay_go.ParallelFor(n, func(v int) {
+		lo, hi := offs[v], offs[v]+deg[v]
+		GT[v] = backing[lo:hi:hi]
+		sort.Ints(GT[v])
+	})
 	return GT
 }
 
+// TransposeOrShare returns G itself when it is known to be symmetric (G == GT), and TransposeAdj(G) otherwise
+func TransposeOrShare(G [][]int, symmetric bool) [][]int {
+	if symmetric {
+		return G
+	}
+	return TransposeAdj(G)
+}
+
+// IsSymmetric reports whether every edge (u, v) of G also appears as (v, u), whatever the order of the neighbor lists
+func IsSymmetric(G [][]int) bool {
+	// the lookups binary-search: keep the sorted lists (.bin files, generators), sort copies of the others (.txt files)
+	sorted := make([][]int, len(G))
+	parlay_go.ParallelFor(len(G), func(u int) {
+		sorted[u] = G[u]
+		if !sort.IntsAreSorted(G[u]) {
+			sorted[u] = slices.Clone(G[u])
+			sort.Ints(sorted[u])
+		}
+	})
+	var bad int32
+	parlay_go.ParallelFor(len(G), func(u int) {
+		for _, v := range G[u] {
+			if atomic.LoadInt32(&bad) != 0 {
+				return
+			}
+			nbrs := sorted[v]
+			i := sort.SearchInts(nbrs, u)
+			if i == len(nbrs) || nbrs[i] != u {
+				atomic.StoreInt32(&bad, 1)
+				return
+			}
+		}
+	})
+	return bad == 0
+}
+
 // FlattenCSR takes an adjacency list and produces
-// the CSR offsets+edges arrays.
+// the CSR offsets+edges arrays.
 func FlattenCSR(G [][]int) ([]int, []int) {
 	n := len(G)
+	// degrees, then a parallel prefix sum gives the offsets
+	deg := make([]int, n)
+	parlay_go.ParallelFor(n, func(u int) {
+		deg[u] = len(G[u])
+	})
+	starts, total := parlay_go.Scan(deg)
 	// make the offsets array one longer than the number of vertices
-	offs := make([]int, n+1)
-
-	// (optional) pre-allocate edges so we don’t keep growing the slice
-	total := 0
-	for _, nbrs := range G {
-		total += len(nbrs)
-	}
-	edges := make([]int, 0, total)
+	offs := append(starts, total)
 
-	// build prefix sums and edge list
-	for u, nbrs := range G {
-		offs[u+1] = offs[u] + len(nbrs)
-		edges = append(edges, nbrs...)
-	}
+	// every vertex copies its neighbors into its own range
+	edges := make([]int, total)
+	parlay_go.ParallelFor(n, func(u int) {
+		copy(edges[offs[u]:offs[u+1]], G[u])
+	})
 
 	// now *actually* return the two slices
 	return offs, edges
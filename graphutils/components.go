@@ -0,0 +1,130 @@
+package graphutils
+
+import (
+	"cluster_bfs_go/parlay_go"
+	"sync/atomic"
+)
+
+// ConnectedComponents labels every vertex with the smallest vertex ID in its component
+// It is a lock-free parallel union-find: roots are linked from the larger ID to the smaller one with CAS,
+// so the final root of each component is its minimum vertex. G is assumed to be symmetric.
+func ConnectedComponents(G [][]int) []int {
+	n := len(G)
+	parent := make([]int64, n)
+	parlay_go.ParallelFor(n, func(i int) {
+		parent[i] = int64(i)
+	})
+
+	// find follows parent pointers to the root, halving the path on the way
+	find := func(x int64) int64 {
+		for {
+			p := atomic.LoadInt64(&parent[x])
+			if p == x {
+				return x
+			}
+			gp := atomic.LoadInt64(&parent[p])
+			atomic.CompareAndSwapInt64(&parent[x], p, gp)
+			x = gp
+		}
+	}
+
+	// union hooks the larger root under the smaller one, retrying if another goroutine moved a root first
+	union := func(u, v int64) {
+		for {
+			ru, rv := find(u), find(v)
+			if ru == rv {
+				return
+			}
+			if ru < rv {
+				ru, rv = rv, ru
+			}
+			if atomic.CompareAndSwapInt64(&parent[ru], ru, rv) {
+				return
+			}
+		}
+	}
+
+	parlay_go.ParallelFor(n, func(u int) {
+		for _, v := range G[u] {
+			if u < v { // each undirected edge once
+				union(int64(u), int64(v))
+			}
+		}
+	})
+
+	labels := make([]int, n)
+	parlay_go.ParallelFor(n, func(v int) {
+		labels[v] = int(find(int64(v)))
+	})
+	return labels
+}
+
+// ComponentSizes returns, for every label, the number of vertices carrying it
+func ComponentSizes(labels []int) []int {
+	sizes := make([]int, len(labels))
+	for _, l := range labels {
+		sizes[l]++
+	}
+	return sizes
+}
+
+// LargestComponentLabel returns the label of the largest component and its size
+func LargestComponentLabel(labels []int) (label, size int) {
+	label = -1
+	for l, s := range ComponentSizes(labels) {
+		if s > size {
+			label, size = l, s
+		}
+	}
+	return label, size
+}
+
+// MarkSmallComponents mirrors remove_smallCC in utils.h: mark[v] is true when v lies in a component
+// with fewer than minSize vertices. minSize <= 0 marks everything outside the largest component.
+func MarkSmallComponents(G [][]int, minSize int) []bool {
+	labels := ConnectedComponents(G)
+	mark := make([]bool, len(G))
+	if minSize <= 0 {
+		largest, _ := LargestComponentLabel(labels)
+		parlay_go.ParallelFor(len(G), func(v int) {
+			mark[v] = labels[v] != largest
+		})
+		return mark
+	}
+	sizes := ComponentSizes(labels)
+	parlay_go.ParallelFor(len(G), func(v int) {
+		mark[v] = sizes[labels[v]] < minSize
+	})
+	return mark
+}
+
+// LargestComponent extracts the largest connected component of G as a new CSR
+// Vertices keep their relative order; orig[i] is the original ID of new vertex i
+func LargestComponent(G [][]int) (offsets []uint64, edges []uint32, orig []int) {
+	labels := ConnectedComponents(G)
+	largest, _ := LargestComponentLabel(labels)
+	keep := make([]bool, len(G))
+	parlay_go.ParallelFor(len(G), func(v int) {
+		keep[v] = labels[v] == largest
+	})
+	orig = parlay_go.PackIndex(keep)
+	newID := make([]int, len(G))
+	parlay_go.ParallelFor(len(orig), func(i int) {
+		newID[orig[i]] = i
+	})
+
+	// Degrees → offsets by prefix sum; a component is closed under edges, so no neighbor is dropped
+	offsets = make([]uint64, len(orig)+1)
+	for i, v := range orig {
+		offsets[i+1] = offsets[i] + uint64(len(G[v]))
+	}
+	edges = make([]uint32, offsets[len(orig)])
+	parlay_go.ParallelFor(len(orig), func(i int) {
+		pos := offsets[i]
+		for _, u := range G[orig[i]] {
+			edges[pos] = uint32(newID[u])
+			pos++
+		}
+	})
+	return offsets, edges, orig
+}
@@ -0,0 +1,49 @@
+package graphutils
+
+import "testing"
+
+// Three components: {0,1,2} (path), {3,4} (edge) and the isolated vertex 5
+var threeComponents = [][]int{
+	{1},
+	{0, 2},
+	{1},
+	{4},
+	{3},
+	{},
+}
+
+func TestConnectedComponents(t *testing.T) {
+	want := []int{0, 0, 0, 3, 3, 5}
+	got := ConnectedComponents(threeComponents)
+	for v := range want {
+		if got[v] != want[v] {
+			t.Fatalf("labels = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMarkSmallComponents(t *testing.T) {
+	outside := MarkSmallComponents(threeComponents, 0)
+	small := MarkSmallComponents(threeComponents, 2)
+	for v, want := range []bool{false, false, false, true, true, true} {
+		if outside[v] != want {
+			t.Fatalf("outside largest: mark = %v", outside)
+		}
+	}
+	for v, want := range []bool{false, false, false, false, false, true} {
+		if small[v] != want {
+			t.Fatalf("minSize 2: mark = %v", small)
+		}
+	}
+}
+
+func TestLargestComponent(t *testing.T) {
+	offs, edges, orig := LargestComponent(threeComponents)
+	if len(orig) != 3 || len(offs) != 4 || len(edges) != 4 {
+		t.Fatalf("got n=%d, m=%d, orig=%v", len(offs)-1, len(edges), orig)
+	}
+	H := BuildAdjFromCSR(offs, edges)
+	if len(H[1]) != 2 || H[1][0] != 0 || H[1][1] != 2 {
+		t.Fatalf("middle vertex has neighbors %v", H[1])
+	}
+}
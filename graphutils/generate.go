@@ -0,0 +1,173 @@
+package graphutils
+
+import (
+	"math/rand/v2"
+	"sort"
+)
+
+// Synthetic graph generators
+// Every generator is deterministic for a given seed and returns a symmetric CSR (same layout as ReadGraphFromBin)
+// with sorted neighbor lists, no self-loops and no duplicate edges.
+
+// newRand returns the generator-local RNG for seed
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, 0x9e3779b97f4a7c15))
+}
+
+// SymmetricCSR builds a symmetric CSR on n vertices from a list of undirected edges
+// Self-loops and duplicates are dropped
+func SymmetricCSR(n int, pairs [][2]int) (offsets []uint64, edges []uint32) {
+	adj := make([][]int, n)
+	for _, p := range pairs {
+		u, v := p[0], p[1]
+		if u == v {
+			continue
+		}
+		adj[u] = append(adj[u], v)
+		adj[v] = append(adj[v], u)
+	}
+	offsets = make([]uint64, n+1)
+	edges = make([]uint32, 0, 2*len(pairs))
+	for u := range adj {
+		sort.Ints(adj[u])
+		for i, v := range adj[u] {
+			if i > 0 && v == adj[u][i-1] {
+				continue
+			}
+			edges = append(edges, uint32(v))
+		}
+		offsets[u+1] = uint64(len(edges))
+	}
+	return offsets, edges
+}
+
+// PathGraph: 0 - 1 - … - (n−1)
+func PathGraph(n int) ([]uint64, []uint32) {
+	pairs := make([][2]int, 0, n)
+	for v := 1; v < n; v++ {
+		pairs = append(pairs, [2]int{v - 1, v})
+	}
+	return SymmetricCSR(n, pairs)
+}
+
+// CycleGraph: a path with the edge (n−1) - 0 closing it
+func CycleGraph(n int) ([]uint64, []uint32) {
+	pairs := make([][2]int, 0, n)
+	for v := 0; v < n; v++ {
+		pairs = append(pairs, [2]int{v, (v + 1) % n})
+	}
+	return SymmetricCSR(n, pairs)
+}
+
+// StarGraph: vertex 0 connected to every other vertex
+func StarGraph(n int) ([]uint64, []uint32) {
+	pairs := make([][2]int, 0, n)
+	for v := 1; v < n; v++ {
+		pairs = append(pairs, [2]int{0, v})
+	}
+	return SymmetricCSR(n, pairs)
+}
+
+// Grid2D: rows × cols 4-neighbor grid; vertex (i, j) is i*cols + j
+func Grid2D(rows, cols int) ([]uint64, []uint32) {
+	return Grid3D(rows, cols, 1)
+}
+
+// Grid3D: x × y × z 6-neighbor grid; vertex (i, j, k) is (i*y + j)*z + k
+func Grid3D(x, y, z int) ([]uint64, []uint32) {
+	id := func(i, j, k int) int { return (i*y+j)*z + k }
+	var pairs [][2]int
+	for i := 0; i < x; i++ {
+		for j := 0; j < y; j++ {
+			for k := 0; k < z; k++ {
+				if i+1 < x {
+					pairs = append(pairs, [2]int{id(i, j, k), id(i+1, j, k)})
+				}
+				if j+1 < y {
+					pairs = append(pairs, [2]int{id(i, j, k), id(i, j+1, k)})
+				}
+				if k+1 < z {
+					pairs = append(pairs, [2]int{id(i, j, k), id(i, j, k+1)})
+				}
+			}
+		}
+	}
+	return SymmetricCSR(x*y*z, pairs)
+}
+
+// ErdosRenyi: G(n, m), m undirected edges with endpoints drawn uniformly at random
+// (self-loops and repeated draws are dropped, so the result can have slightly fewer edges; n = 0 gives the empty graph)
+func ErdosRenyi(n, m int, seed uint64) ([]uint64, []uint32) {
+	if n == 0 {
+		return SymmetricCSR(0, nil)
+	}
+	rng := newRand(seed)
+	pairs := make([][2]int, m)
+	for i := range pairs {
+		pairs[i] = [2]int{rng.IntN(n), rng.IntN(n)}
+	}
+	return SymmetricCSR(n, pairs)
+}
+
+// RMAT: 2^scale vertices and edgeFactor·2^scale edge draws, each recursively placed into a quadrant
+// of the adjacency matrix with probabilities a, b, c and 1−a−b−c
+func RMAT(scale, edgeFactor int, a, b, c float64, seed uint64) ([]uint64, []uint32) {
+	rng := newRand(seed)
+	n := 1 << scale
+	pairs := make([][2]int, n*edgeFactor)
+	for i := range pairs {
+		u, v := 0, 0
+		for bit := scale - 1; bit >= 0; bit-- {
+			p := rng.Float64()
+			switch {
+			case p < a: // top-left: no bits set
+			case p < a+b:
+				v |= 1 << bit
+			case p < a+b+c:
+				u |= 1 << bit
+			default:
+				u |= 1 << bit
+				v |= 1 << bit
+			}
+		}
+		pairs[i] = [2]int{u, v}
+	}
+	return SymmetricCSR(n, pairs)
+}
+
+// Kronecker: RMAT with the Graph500 initiator (a, b, c) = (0.57, 0.19, 0.19)
+func Kronecker(scale, edgeFactor int, seed uint64) ([]uint64, []uint32) {
+	return RMAT(scale, edgeFactor, 0.57, 0.19, 0.19, seed)
+}
+
+// BarabasiAlbert: preferential attachment; every new vertex links to k distinct earlier vertices chosen
+// proportionally to their degree. The first k+1 vertices form a clique.
+func BarabasiAlbert(n, k int, seed uint64) ([]uint64, []uint32) {
+	rng := newRand(seed)
+	var pairs [][2]int
+	// endpoints lists every edge endpoint once, so a uniform pick from it is degree-proportional
+	var endpoints []int
+	for u := 0; u <= k && u < n; u++ {
+		for v := 0; v < u; v++ {
+			pairs = append(pairs, [2]int{u, v})
+			endpoints = append(endpoints, u, v)
+		}
+	}
+	for u := k + 1; u < n; u++ {
+		chosen := make(map[int]struct{}, k)
+		for len(chosen) < k {
+			chosen[endpoints[rng.IntN(len(endpoints))]] = struct{}{}
+		}
+		// map iteration order is random; sort so the output only depends on seed
+		targets := make([]int, 0, k)
+		for v := range chosen {
+			targets = append(targets, v)
+		}
+		sort.Ints(targets)
+		for _, v := range targets {
+			pairs = append(pairs, [2]int{u, v})
+			endpoints = append(endpoints, u, v)
+		}
+	}
+	return SymmetricCSR(n, pairs)
+}
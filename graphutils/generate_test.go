@@ -0,0 +1,100 @@
+package graphutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+// isSymmetricCSR checks that every edge appears in both directions, without self-loops or duplicates
+func isSymmetricCSR(t *testing.T, name string, offs []uint64, edges []uint32) {
+	t.Helper()
+	G := BuildAdjFromCSR(offs, edges)
+	has := make(map[[2]int]bool, len(edges))
+	for u, nbrs := range G {
+		for i, v := range nbrs {
+			if v == u {
+				t.Fatalf("%s: self-loop at %d", name, u)
+			}
+			if i > 0 && nbrs[i-1] >= v {
+				t.Fatalf("%s: neighbors of %d not strictly sorted: %v", name, u, nbrs)
+			}
+			has[[2]int{u, v}] = true
+		}
+	}
+	for e := range has {
+		if !has[[2]int{e[1], e[0]}] {
+			t.Fatalf("%s: edge %v has no reverse", name, e)
+		}
+	}
+}
+
+func TestGeneratorsAreSymmetric(t *testing.T) {
+	cases := map[string]func() ([]uint64, []uint32){
+		"path":      func() ([]uint64, []uint32) { return PathGraph(10) },
+		"cycle":     func() ([]uint64, []uint32) { return CycleGraph(10) },
+		"star":      func() ([]uint64, []uint32) { return StarGraph(10) },
+		"grid2d":    func() ([]uint64, []uint32) { return Grid2D(4, 5) },
+		"grid3d":    func() ([]uint64, []uint32) { return Grid3D(3, 3, 3) },
+		"er":        func() ([]uint64, []uint32) { return ErdosRenyi(100, 300, 1) },
+		"rmat":      func() ([]uint64, []uint32) { return RMAT(7, 8, 0.5, 0.2, 0.2, 1) },
+		"kronecker": func() ([]uint64, []uint32) { return Kronecker(7, 8, 1) },
+		"ba":        func() ([]uint64, []uint32) { return BarabasiAlbert(100, 3, 1) },
+	}
+	for name, gen := range cases {
+		offs, edges := gen()
+		isSymmetricCSR(t, name, offs, edges)
+	}
+}
+
+func TestGeneratorShapes(t *testing.T) {
+	if offs, edges := PathGraph(10); len(offs) != 11 || len(edges) != 18 {
+		t.Fatalf("path: n=%d m=%d", len(offs)-1, len(edges))
+	}
+	if offs, edges := Grid3D(3, 3, 3); len(offs) != 28 || len(edges) != 2*54 {
+		t.Fatalf("grid3d: n=%d m=%d", len(offs)-1, len(edges))
+	}
+	if offs, _ := StarGraph(10); offs[1] != 9 {
+		t.Fatalf("star center has degree %d", offs[1])
+	}
+	if offs, edges := ErdosRenyi(0, 5, 1); len(offs) != 1 || len(edges) != 0 {
+		t.Fatalf("er on no vertices: n=%d m=%d", len(offs)-1, len(edges))
+	}
+}
+
+func TestGeneratorsAreDeterministic(t *testing.T) {
+	o1, e1 := Kronecker(8, 4, 42)
+	o2, e2 := Kronecker(8, 4, 42)
+	if !reflect.DeepEqual(o1, o2) || !reflect.DeepEqual(e1, e2) {
+		t.Fatal("same seed produced different RMAT graphs")
+	}
+	o1, e1 = BarabasiAlbert(200, 2, 7)
+	o2, e2 = BarabasiAlbert(200, 2, 7)
+	if !reflect.DeepEqual(o1, o2) || !reflect.DeepEqual(e1, e2) {
+		t.Fatal("same seed produced different BA graphs")
+	}
+	_, e3 := ErdosRenyi(200, 400, 1)
+	_, e4 := ErdosRenyi(200, 400, 2)
+	if reflect.DeepEqual(e3, e4) {
+		t.Fatal("different seeds produced the same ER graph")
+	}
+}
+
+func TestTransposeMatchesSymmetricGraph(t *testing.T) {
+	G := BuildAdjFromCSR(Kronecker(8, 8, 3))
+	if !IsSymmetric(G) {
+		t.Fatal("generated graph should be symmetric")
+	}
+	if GT := TransposeAdj(G); !reflect.DeepEqual(G, GT) {
+		t.Fatal("transpose of a symmetric graph should equal the graph")
+	}
+	offs, edges := FlattenCSR(G)
+	if offs[len(G)] != len(edges) {
+		t.Fatalf("last offset %d != m %d", offs[len(G)], len(edges))
+	}
+	if IsSymmetric([][]int{{1}, {}}) {
+		t.Fatal("a single directed edge is not symmetric")
+	}
+	if !IsSymmetric([][]int{{2, 1}, {0}, {0}}) {
+		t.Fatal("unsorted neighbor lists of a symmetric graph (as read from .txt files) were rejected")
+	}
+}
@@ -0,0 +1,87 @@
+package graphutils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Graph files are picked by extension:
+// .txt / .adj  plain-text adjacency lists (ReadAdjText / WriteAdjText)
+// .bytepd      the "bytepd" binary (ReadBytePD, read only)
+// anything else the CSR binary (ReadGraphFromBin / WriteGraphToBin)
+
+// ReadGraph loads a graph file in any of the supported formats
+func ReadGraph(path string) (offsets []uint64, edges []uint32, err error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".adj":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open %s: %w", path, err)
+		}
+		defer f.Close()
+		offsets, edges, err = ReadAdjText(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		return offsets, edges, nil
+	case ".bytepd":
+		offs, edges64, err := ReadBytePD(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		edges = make([]uint32, len(edges64))
+		for i, v := range edges64 {
+			if v >= uint64(len(offs)-1) {
+				return nil, nil, fmt.Errorf("%s: edge %d points to vertex %d of %d", path, i, v, len(offs)-1)
+			}
+			edges[i] = uint32(v)
+		}
+		return offs, edges, nil
+	default:
+		return ReadGraphFromBin(path)
+	}
+}
+
+// WriteGraph saves a CSR as text for .txt / .adj files and as the CSR binary otherwise
+func WriteGraph(path string, offsets []uint64, edges []uint32) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".adj":
+		return WriteAdjText(path, offsets, edges)
+	case ".bytepd":
+		return fmt.Errorf("%s: writing bytepd files is not supported", path)
+	default:
+		return WriteGraphToBin(path, offsets, edges)
+	}
+}
+
+// WriteAdjText writes a CSR in the format read by ReadAdjText (one "src nbrs…" line per vertex)
+func WriteAdjText(path string, offsets []uint64, edges []uint32) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	var line []byte
+	for v := 0; v+1 < len(offsets); v++ {
+		line = strconv.AppendInt(line[:0], int64(v), 10)
+		for _, u := range edges[offsets[v]:offsets[v+1]] {
+			line = append(line, ' ')
+			line = strconv.AppendUint(line, uint64(u), 10)
+		}
+		line = append(line, '\n')
+		if _, err = w.Write(line); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	return w.Flush()
+}
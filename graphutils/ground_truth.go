@@ -0,0 +1,55 @@
+package graphutils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// DistancePair is one ground-truth entry: the true distance D between U and V
+type DistancePair struct {
+	U, V, D int
+}
+
+// ReadGroundTruth mirrors read_ground_truth in utils.h
+/*
+Data format (whitespace separated):
+<count>
+<u v d>   (count lines)
+*/
+func ReadGroundTruth(path string) ([]DistancePair, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Split(bufio.ScanWords)
+	next := func() (int, error) {
+		if !sc.Scan() {
+			if err := sc.Err(); err != nil {
+				return 0, err
+			}
+			return 0, fmt.Errorf("unexpected end of file")
+		}
+		return strconv.Atoi(sc.Text())
+	}
+
+	count, err := next()
+	if err != nil {
+		return nil, fmt.Errorf("%s: bad header: %w", path, err)
+	}
+	pairs := make([]DistancePair, count)
+	for i := range pairs {
+		var vals [3]int
+		for j := range vals {
+			if vals[j], err = next(); err != nil {
+				return nil, fmt.Errorf("%s: entry %d: %w", path, i, err)
+			}
+		}
+		pairs[i] = DistancePair{U: vals[0], V: vals[1], D: vals[2]}
+	}
+	return pairs, nil
+}
@@ -1,9 +1,13 @@
 package graphutils
 
 import (
+	"bufio"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // ReadGraphFromBin read graph data from bin files "Sequentially" in the below format
@@ -33,8 +37,6 @@ func ReadGraphFromBin(path string) (offsets []uint64, edges []uint32, err error)
 	if err = binary.Read(f, binary.LittleEndian, &sizes); err != nil {
 		return
 	}
-	// Print out the header values
-	fmt.Printf("DEBUG: n=%d, m=%d, sizes=%d\n", n, m, sizes)
 	// Sanity check: bytes for offsets + edges + header should match
 	expected := (n+1)*8 + m*4 + 3*8
 	if sizes != expected {
@@ -78,7 +80,6 @@ func ReadBytePD(path string) (offsets []uint64, edges []uint64, err error) {
 	if err = binary.Read(f, binary.LittleEndian, &m); err != nil {
 		return
 	}
-	fmt.Printf("DEBUG bytepd → n=%d, m=%d\n", n, m)
 
 	// 2) Read per‑vertex byte counts
 	degree := make([]uint64, n)
@@ -103,3 +104,47 @@ func ReadBytePD(path string) (offsets []uint64, edges []uint64, err error) {
 
 	return offsets, edges, nil
 }
+
+// ReadAdjText parses the plain-text adjacency format of data/test.txt (the input of data/test_graph.py)
+/*
+Data format (one line per vertex, blank lines ignored):
+src nbr_1 nbr_2 … nbr_d
+*/
+// Vertices without a line get no out-edges; n is one more than the largest ID seen
+func ReadAdjText(r io.Reader) (offsets []uint64, edges []uint32, err error) {
+	adj := map[int][]uint32{}
+	n := 0
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1<<20), 1<<30)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		ids := make([]int, len(fields))
+		for i, f := range fields {
+			if ids[i], err = strconv.Atoi(f); err != nil || ids[i] < 0 {
+				return nil, nil, fmt.Errorf("line %d: bad vertex %q", line, f)
+			}
+			n = max(n, ids[i]+1)
+		}
+		src := ids[0]
+		for _, v := range ids[1:] {
+			adj[src] = append(adj[src], uint32(v))
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Build CSR in vertex order
+	offsets = make([]uint64, n+1)
+	for v := 0; v < n; v++ {
+		offsets[v+1] = offsets[v] + uint64(len(adj[v]))
+		edges = append(edges, adj[v]...)
+	}
+	if edges == nil {
+		edges = []uint32{}
+	}
+	return offsets, edges, nil
+}
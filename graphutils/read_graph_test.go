@@ -0,0 +1,149 @@
+package graphutils
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// data/toy_graph.bin is data/test.txt converted by data/test_graph.py
+func TestToyGraphMatchesText(t *testing.T) {
+	f, err := os.Open("../data/test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	offs, edges, err := ReadAdjText(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsBin, edgesBin, err := ReadGraphFromBin("../data/toy_graph.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(offs, offsBin) || !reflect.DeepEqual(edges, edgesBin) {
+		t.Fatalf("text %v %v, bin %v %v", offs, edges, offsBin, edgesBin)
+	}
+}
+
+func TestReadAdjText(t *testing.T) {
+	offs, edges, err := ReadAdjText(strings.NewReader("2 0\n\n0 1 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(offs, []uint64{0, 2, 2, 3}) || !reflect.DeepEqual(edges, []uint32{1, 2, 0}) {
+		t.Fatalf("got %v %v", offs, edges)
+	}
+	if _, _, err := ReadAdjText(strings.NewReader("0 x\n")); err == nil {
+		t.Fatal("expected an error for a non-numeric vertex")
+	}
+}
+
+func TestReadGraphFromBinErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := ReadGraphFromBin(filepath.Join(dir, "missing.bin")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+	// header claims a size that does not match n and m
+	bad := filepath.Join(dir, "bad.bin")
+	buf := make([]byte, 24)
+	binary.LittleEndian.PutUint64(buf[0:], 2)
+	binary.LittleEndian.PutUint64(buf[8:], 1)
+	binary.LittleEndian.PutUint64(buf[16:], 7)
+	if err := os.WriteFile(bad, buf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadGraphFromBin(bad); err == nil {
+		t.Fatal("expected a size mismatch error")
+	}
+	// truncated body
+	binary.LittleEndian.PutUint64(buf[16:], 3*8+3*8+4)
+	if err := os.WriteFile(bad, buf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadGraphFromBin(bad); err == nil {
+		t.Fatal("expected an error for a truncated file")
+	}
+}
+
+func TestReadBytePD(t *testing.T) {
+	// n=3, m=3: degrees 2, 0, 1; neighbors 1 2 | - | 0
+	var words []uint64
+	words = append(words, 3, 3, 2, 0, 1, 1, 2, 0)
+	path := filepath.Join(t.TempDir(), "g.bytepd")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := binary.Write(f, binary.LittleEndian, words); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	offs, edges, err := ReadBytePD(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(offs, []uint64{0, 2, 2, 3}) || !reflect.DeepEqual(edges, []uint64{1, 2, 0}) {
+		t.Fatalf("got %v %v", offs, edges)
+	}
+}
+
+func TestGraphFileFormats(t *testing.T) {
+	offs, edges := ErdosRenyi(50, 120, 1)
+	dir := t.TempDir()
+	for _, name := range []string{"g.bin", "g.txt", "g.adj"} {
+		path := filepath.Join(dir, name)
+		if err := WriteGraph(path, offs, edges); err != nil {
+			t.Fatal(err)
+		}
+		offs2, edges2, err := ReadGraph(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(offs, offs2) || !reflect.DeepEqual(edges, edges2) {
+			t.Fatalf("%s: graph changed after a write/read round trip", name)
+		}
+	}
+	if err := WriteGraph(filepath.Join(dir, "g.bytepd"), offs, edges); err == nil {
+		t.Fatal("expected an error writing bytepd")
+	}
+}
+
+// loading a generated graph: the binary reader and the adjacency-list construction
+func BenchmarkLoadGraph(b *testing.B) {
+	offs, edges := Kronecker(14, 16, 1)
+	path := filepath.Join(b.TempDir(), "kron.bin")
+	if err := WriteGraphToBin(path, offs, edges); err != nil {
+		b.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("ReadGraphFromBin", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(info.Size())
+		for i := 0; i < b.N; i++ {
+			if _, _, err := ReadGraphFromBin(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("BuildAdjFromCSR", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			BuildAdjFromCSR(offs, edges)
+		}
+	})
+	b.Run("TransposeAdj", func(b *testing.B) {
+		G := BuildAdjFromCSR(offs, edges)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			TransposeAdj(G)
+		}
+	})
+}
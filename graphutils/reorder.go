@@ -0,0 +1,238 @@
+package graphutils
+
+import (
+	"cluster_bfs_go/parlay_go"
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Reordering records how a graph was relabeled
+// Orders[i] is the original ID of new vertex i, and NewID[v] is the new ID of original vertex v
+type Reordering struct {
+	Orders []int
+	NewID  []int
+}
+
+// Reorder relabels G with the named strategy ("degree", "bfs", "random" or "gorder")
+// and returns the relabeled graph together with the permutation used; seed is the RNG seed of "random"
+func Reorder(G [][]int, method string, seed uint64) ([][]int, *Reordering, error) {
+	var orders []int
+	switch method {
+	case "degree":
+		orders = OrderByDegrees(G)
+	case "bfs":
+		orders = OrderByBFS(G)
+	case "random":
+		orders = OrderRandom(len(G), seed)
+	case "gorder":
+		orders = OrderGorderLite(G, 5)
+	default:
+		return nil, nil, fmt.Errorf("unknown reordering %q (want degree, bfs, random or gorder)", method)
+	}
+	H, newID := RelabelGraph(G, orders)
+	return H, &Reordering{Orders: orders, NewID: newID}, nil
+}
+
+// hash32 is parlay::hash32, used to break ties between vertices of equal degree
+func hash32(a uint32) uint32 {
+	a = (a + 0x7ed55d16) + (a << 12)
+	a = (a ^ 0xc761c23c) ^ (a >> 19)
+	a = (a + 0x165667b1) + (a << 5)
+	a = (a + 0xd3a2646c) ^ (a << 9)
+	a = (a + 0xfd7046c5) + (a << 3)
+	a = (a ^ 0xb55a4f09) ^ (a >> 16)
+	return a
+}
+
+// OrderByDegrees mirrors order_by_degrees in utils.h: vertices sorted by degree (descending), ties broken by hash
+func OrderByDegrees(G [][]int) []int {
+	n := len(G)
+	orders := make([]int, n)
+	parlay_go.ParallelFor(n, func(i int) {
+		orders[i] = i
+	})
+	sort.Slice(orders, func(i, j int) bool {
+		a, b := orders[i], orders[j]
+		if len(G[a]) != len(G[b]) {
+			return len(G[a]) > len(G[b])
+		}
+		return hash32(uint32(a)) > hash32(uint32(b))
+	})
+	return orders
+}
+
+// OrderByBFS numbers vertices in BFS visiting order
+// Each connected component is started from its highest-degree vertex, so the giant component comes first
+func OrderByBFS(G [][]int) []int {
+	n := len(G)
+	visited := make([]bool, n)
+	orders := make([]int, 0, n)
+	for _, src := range OrderByDegrees(G) {
+		if visited[src] {
+			continue
+		}
+		visited[src] = true
+		head := len(orders)
+		orders = append(orders, src)
+		// orders doubles as the FIFO queue for this component
+		for ; head < len(orders); head++ {
+			for _, v := range G[orders[head]] {
+				if !visited[v] {
+					visited[v] = true
+					orders = append(orders, v)
+				}
+			}
+		}
+	}
+	return orders
+}
+
+// OrderRandom returns a uniformly random ordering of 0…n−1, the same for the same seed
+func OrderRandom(n int, seed uint64) []int {
+	return newRand(seed).Perm(n)
+}
+
+// gorderItem is a (score, vertex) entry in the Gorder priority queue
+type gorderItem struct {
+	score, v int
+}
+
+// gorderHeap is a max-heap on score; stale entries are skipped lazily when popped
+type gorderHeap []gorderItem
+
+func (h gorderHeap) Len() int { return len(h) }
+func (h gorderHeap) Less(i, j int) bool {
+	if h[i].score != h[j].score {
+		return h[i].score > h[j].score
+	}
+	return h[i].v < h[j].v
+}
+func (h gorderHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *gorderHeap) Push(x interface{}) { *h = append(*h, x.(gorderItem)) }
+func (h *gorderHeap) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// OrderGorderLite is a simplified sequential Gorder
+// It greedily appends the unplaced vertex sharing the most neighbors (and neighbors of neighbors) with the
+// last `window` placed vertices; hubs with degree > sqrt(n) are not expanded to keep updates cheap
+func OrderGorderLite(G [][]int, window int) []int {
+	n := len(G)
+	if n == 0 {
+		return []int{}
+	}
+	hubCap := int(math.Sqrt(float64(n)))
+	score := make([]int, n)
+	placed := make([]bool, n)
+	byDegree := OrderByDegrees(G) // fallback when the queue runs dry (e.g., a new component)
+	next := 0
+	h := &gorderHeap{}
+
+	// bump adds delta to the score of every unplaced vertex related to v
+	bump := func(v, delta int) {
+		touch := func(w int) {
+			if placed[w] {
+				return
+			}
+			score[w] += delta
+			heap.Push(h, gorderItem{score[w], w})
+		}
+		for _, u := range G[v] {
+			touch(u)
+			if len(G[u]) <= hubCap {
+				for _, w := range G[u] {
+					touch(w)
+				}
+			}
+		}
+	}
+
+	orders := make([]int, 0, n)
+	for len(orders) < n {
+		v := -1
+		for h.Len() > 0 {
+			it := heap.Pop(h).(gorderItem)
+			if !placed[it.v] && it.score == score[it.v] {
+				v = it.v
+				break
+			}
+		}
+		if v == -1 {
+			for placed[byDegree[next]] {
+				next++
+			}
+			v = byDegree[next]
+		}
+		placed[v] = true
+		orders = append(orders, v)
+		bump(v, 1)
+		// slide the window: the vertex that falls out no longer contributes
+		if len(orders) > window {
+			bump(orders[len(orders)-1-window], -1)
+		}
+	}
+	return orders
+}
+
+// InverseOrder builds get_order: InverseOrder(orders)[orders[i]] = i
+func InverseOrder(orders []int) []int {
+	inv := make([]int, len(orders))
+	parlay_go.ParallelFor(len(orders), func(i int) {
+		inv[orders[i]] = i
+	})
+	return inv
+}
+
+// RelabelGraph renames vertices so that orders[i] becomes vertex i
+// Neighbor lists of the new graph are sorted by their new IDs; NewID maps original IDs to new IDs
+func RelabelGraph(G [][]int, orders []int) (H [][]int, newID []int) {
+	newID = InverseOrder(orders)
+	H = make([][]int, len(G))
+	parlay_go.ParallelFor(len(G), func(i int) {
+		old := orders[i]
+		nbrs := make([]int, len(G[old]))
+		for j, u := range G[old] {
+			nbrs[j] = newID[u]
+		}
+		sort.Ints(nbrs)
+		H[i] = nbrs
+	})
+	return H, newID
+}
+
+// MapVertices renames every vertex in vs through perm (NewID for original → new, Orders for new → original)
+// The seed sentinel n = len(perm) is kept as is
+func MapVertices(vs []int, perm []int) []int {
+	out := make([]int, len(vs))
+	for i, v := range vs {
+		if v == len(perm) {
+			out[i] = v
+			continue
+		}
+		out[i] = perm[v]
+	}
+	return out
+}
+
+// MapSeeds applies MapVertices to every seed batch
+func MapSeeds(seeds [][]int, perm []int) [][]int {
+	out := make([][]int, len(seeds))
+	for i, batch := range seeds {
+		out[i] = MapVertices(batch, perm)
+	}
+	return out
+}
+
+// ToOriginalOrder moves per-vertex results (e.g., D or S) computed on a relabeled graph back to original IDs
+func ToOriginalOrder[T any](vals []T, re *Reordering) []T {
+	out := make([]T, len(vals))
+	parlay_go.ParallelFor(len(vals), func(i int) {
+		out[re.Orders[i]] = vals[i]
+	})
+	return out
+}
@@ -0,0 +1,76 @@
+package graphutils
+
+import (
+	"slices"
+	"sort"
+	"testing"
+)
+
+// A star centered at 3 plus the edge 1-2, stored symmetrically
+var starPlusEdge = [][]int{
+	{3},
+	{2, 3},
+	{1, 3},
+	{0, 1, 2, 4},
+	{3},
+}
+
+func TestReorderIsPermutation(t *testing.T) {
+	for _, method := range []string{"degree", "bfs", "random", "gorder"} {
+		H, re, err := Reorder(starPlusEdge, method, 1)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		seen := make([]bool, len(starPlusEdge))
+		for i, v := range re.Orders {
+			if seen[v] {
+				t.Fatalf("%s: vertex %d placed twice", method, v)
+			}
+			seen[v] = true
+			if re.NewID[v] != i {
+				t.Fatalf("%s: NewID[%d]=%d, want %d", method, v, re.NewID[v], i)
+			}
+		}
+		// every edge must survive the relabeling
+		for u, nbrs := range starPlusEdge {
+			got := MapVertices(H[re.NewID[u]], re.Orders)
+			want := append([]int(nil), nbrs...)
+			sort.Ints(got)
+			sort.Ints(want)
+			if len(got) != len(want) {
+				t.Fatalf("%s: vertex %d has %v, want %v", method, u, got, want)
+			}
+			for j := range got {
+				if got[j] != want[j] {
+					t.Fatalf("%s: vertex %d has %v, want %v", method, u, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestOrderByDegreesPutsHubFirst(t *testing.T) {
+	if got := OrderByDegrees(starPlusEdge)[0]; got != 3 {
+		t.Fatalf("first vertex = %d, want the hub 3", got)
+	}
+	if got := OrderByBFS(starPlusEdge)[0]; got != 3 {
+		t.Fatalf("BFS order starts at %d, want the hub 3", got)
+	}
+}
+
+// The same seed gives the same random order, so -order random runs can be replayed
+func TestOrderRandomIsSeeded(t *testing.T) {
+	a, b := OrderRandom(100, 7), OrderRandom(100, 7)
+	if !slices.Equal(a, b) {
+		t.Fatal("OrderRandom differs for the same seed")
+	}
+	if slices.Equal(a, OrderRandom(100, 8)) {
+		t.Fatal("OrderRandom ignores its seed")
+	}
+}
+
+func TestUnknownReordering(t *testing.T) {
+	if _, _, err := Reorder(starPlusEdge, "nope", 1); err == nil {
+		t.Fatal("expected an error for an unknown method")
+	}
+}
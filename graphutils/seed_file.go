@@ -0,0 +1,115 @@
+package graphutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Seed files store seed batches so Go and C++ runs (or regression tests) can use identical seeds
+/*
+Text format (default): one batch per line, vertex IDs separated by spaces; blank lines and lines starting with # are ignored
+	# ns=2 k=3
+	17 4 9
+	250 3 250
+JSON format (.json files): an array of batches
+	[[17,4,9],[250,3,250]]
+*/
+
+// ReadSeeds loads seed batches from path; files ending in .json (or starting with '[') are read as JSON
+func ReadSeeds(path string) ([][]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	var seeds [][]int
+	if strings.EqualFold(filepath.Ext(path), ".json") || bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &seeds); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	} else {
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for line := 1; sc.Scan(); line++ {
+			text := strings.TrimSpace(sc.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			fields := strings.Fields(text)
+			batch := make([]int, len(fields))
+			for i, f := range fields {
+				if batch[i], err = strconv.Atoi(f); err != nil {
+					return nil, fmt.Errorf("%s:%d: bad vertex %q", path, line, f)
+				}
+			}
+			seeds = append(seeds, batch)
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("%s: no seed batches", path)
+	}
+	return seeds, nil
+}
+
+// WriteSeeds saves seed batches to path, as JSON for .json files and in the text format otherwise
+func WriteSeeds(path string, seeds [][]int) error {
+	var buf bytes.Buffer
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := json.Marshal(seeds)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	} else {
+		k := 0
+		if len(seeds) > 0 {
+			k = len(seeds[0])
+		}
+		fmt.Fprintf(&buf, "# ns=%d k=%d\n", len(seeds), k)
+		for _, batch := range seeds {
+			for i, v := range batch {
+				if i > 0 {
+					buf.WriteByte(' ')
+				}
+				buf.WriteString(strconv.Itoa(v))
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ValidateSeeds checks that seeds can be run on a graph with n vertices: every batch has the same
+// size k ≤ 64 (one bit per seed) and every vertex is in 0…n−1, or the sentinel n of an unused batch
+func ValidateSeeds(seeds [][]int, n int) error {
+	if len(seeds) == 0 {
+		return fmt.Errorf("no seed batches")
+	}
+	k := len(seeds[0])
+	if k == 0 || k > 64 {
+		return fmt.Errorf("batch size %d out of range 1…64", k)
+	}
+	for b, batch := range seeds {
+		if len(batch) != k {
+			return fmt.Errorf("batch %d has %d seeds, expected %d", b, len(batch), k)
+		}
+		for _, v := range batch {
+			if v < 0 || v > n {
+				return fmt.Errorf("batch %d: vertex %d out of range 0…%d (or the sentinel %d)", b, v, n-1, n)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package graphutils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSeedFileRoundTrip(t *testing.T) {
+	seeds := [][]int{{17, 4, 9}, {250, 3, 250}}
+	dir := t.TempDir()
+	for _, name := range []string{"seeds.txt", "seeds.json"} {
+		path := filepath.Join(dir, name)
+		if err := WriteSeeds(path, seeds); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadSeeds(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, seeds) {
+			t.Fatalf("%s: got %v, want %v", name, got, seeds)
+		}
+	}
+}
+
+func TestReadSeedsRejectsGarbage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.txt")
+	if err := os.WriteFile(path, []byte("1 2 x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSeeds(path); err == nil {
+		t.Fatal("expected a parse error")
+	}
+}
+
+func TestValidateSeeds(t *testing.T) {
+	if err := ValidateSeeds([][]int{{0, 1}, {5, 5}}, 5); err != nil {
+		t.Fatalf("sentinel batch rejected: %v", err)
+	}
+	if err := ValidateSeeds([][]int{{0, 1}, {2}}, 5); err == nil {
+		t.Fatal("ragged batches accepted")
+	}
+	if err := ValidateSeeds([][]int{{0, 6}}, 5); err == nil {
+		t.Fatal("out-of-range vertex accepted")
+	}
+}
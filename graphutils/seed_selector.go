@@ -0,0 +1,128 @@
+package graphutils
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SeedBatches is the result of a seed selection
+type SeedBatches struct {
+	Batches [][]int // ns batches of k seeds; unused batches hold the sentinel n
+	Actual  int     // seeds actually chosen (padding and sentinels excluded)
+	Padding int     // slots of real batches padded with a repeat of the batch's first seed
+	Unused  int     // batches filled with the sentinel n
+}
+
+// Used returns the batches that hold real seeds (the sentinel batches trimmed)
+func (sb *SeedBatches) Used() [][]int {
+	return sb.Batches[:len(sb.Batches)-sb.Unused]
+}
+
+// SeedSelector is a named seed selection strategy
+// Select returns ns batches of k seeds for G, following the pre-allocated [][]int contract of SelectSeeds1:
+// every batch starts with its center (or first seed), short batches are padded with that first seed,
+// and batches that cannot be built hold the sentinel n = len(G).
+type SeedSelector interface {
+	Name() string
+	Select(G [][]int, ns, k int) (*SeedBatches, error)
+}
+
+// SeedOptions configures the selectors built by NewSeedSelector
+type SeedOptions struct {
+	Seed     uint64 // RNG seed (strategies without randomness ignore it)
+	Mark     []bool // vertices never picked (nil: none); updated in place when Disjoint is set
+	Disjoint bool   // never reuse a vertex in two batches
+	R        int    // radius, for strategies that look at R-hop balls (coverage)
+}
+
+// seedStrategy adapts a Seeder method to the SeedSelector interface
+type seedStrategy struct {
+	name string
+	opts SeedOptions
+	run  func(s *Seeder, G [][]int, seeds [][]int, opts SeedOptions) int
+}
+
+func (st *seedStrategy) Name() string { return st.name }
+
+func (st *seedStrategy) Select(G [][]int, ns, k int) (*SeedBatches, error) {
+	n := len(G)
+	if ns < 1 {
+		return nil, fmt.Errorf("%s: need at least one batch, got %d", st.name, ns)
+	}
+	if k < 1 || k > 64 {
+		return nil, fmt.Errorf("%s: batch size %d out of range 1…64", st.name, k)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%s: empty graph", st.name)
+	}
+	// start from all-sentinel batches, so rows a strategy never reaches stay marked as unused
+	seeds := make([][]int, ns)
+	for i := range seeds {
+		seeds[i] = make([]int, k)
+		for j := range seeds[i] {
+			seeds[i][j] = n
+		}
+	}
+	seeder := NewSeeder(st.opts.Seed)
+	seeder.Mark = st.opts.Mark
+	seeder.Disjoint = st.opts.Disjoint
+
+	sb := &SeedBatches{Batches: seeds}
+	sb.Actual = st.run(seeder, G, seeds, st.opts)
+	for _, batch := range seeds {
+		if batch[0] == n {
+			sb.Unused++ // sentinel batches only ever trail the real ones
+			continue
+		}
+		for _, v := range batch[1:] {
+			if v == batch[0] {
+				sb.Padding++
+			}
+		}
+	}
+	return sb, nil
+}
+
+// registry of named strategies
+var seedStrategies = map[string]func(*Seeder, [][]int, [][]int, SeedOptions) int{}
+
+// RegisterSeedStrategy makes a strategy available to NewSeedSelector under name
+// run fills the pre-allocated seeds and returns the number of seeds it chose
+func RegisterSeedStrategy(name string, run func(s *Seeder, G [][]int, seeds [][]int, opts SeedOptions) int) {
+	if _, dup := seedStrategies[name]; dup {
+		panic("graphutils: seed strategy " + name + " registered twice")
+	}
+	seedStrategies[name] = run
+}
+
+// NewSeedSelector returns the registered strategy called name
+func NewSeedSelector(name string, opts SeedOptions) (SeedSelector, error) {
+	run, ok := seedStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown seed strategy %q (have %v)", name, SeedStrategyNames())
+	}
+	return &seedStrategy{name: name, opts: opts, run: run}, nil
+}
+
+// SeedStrategyNames lists the registered strategies in alphabetical order
+func SeedStrategyNames() []string {
+	names := make([]string, 0, len(seedStrategies))
+	for name := range seedStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterSeedStrategy("1hop", func(s *Seeder, G, seeds [][]int, _ SeedOptions) int { return s.SelectSeeds1(G, seeds) })
+	RegisterSeedStrategy("2hop", func(s *Seeder, G, seeds [][]int, _ SeedOptions) int { return s.SelectSeeds2(G, seeds) })
+	RegisterSeedStrategy("3hop", func(s *Seeder, G, seeds [][]int, _ SeedOptions) int { return s.SelectSeeds3(G, seeds) })
+	RegisterSeedStrategy("2hop-heap", func(s *Seeder, G, seeds [][]int, _ SeedOptions) int { return s.SelectSeeds2Heap(G, seeds) })
+	RegisterSeedStrategy("3hop-heap", func(s *Seeder, G, seeds [][]int, _ SeedOptions) int { return s.SelectSeeds3Heap(G, seeds) })
+	RegisterSeedStrategy("top-degree", func(s *Seeder, G, seeds [][]int, _ SeedOptions) int { return s.SelectTopDegree(G, seeds) })
+	RegisterSeedStrategy("k-center", func(s *Seeder, G, seeds [][]int, _ SeedOptions) int { return s.SelectKCenter(G, seeds) })
+	RegisterSeedStrategy("coverage", func(s *Seeder, G, seeds [][]int, o SeedOptions) int {
+		return s.SelectCoverage(G, seeds, max(o.R, 1))
+	})
+}
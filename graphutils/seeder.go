@@ -0,0 +1,83 @@
+package graphutils
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Seeder picks seed batches reproducibly: every random choice comes from its own RNG,
+// so two seeders built with the same Seed choose exactly the same batches on the same graph
+type Seeder struct {
+	Seed     uint64 // RNG seed; log it to replay a run
+	Mark     []bool // vertices never picked (nil: none); in Disjoint mode chosen seeds are marked here too
+	Disjoint bool   // never reuse a vertex in two batches (see SelectSeeds1Disjoint)
+	rng      *rand.Rand
+}
+
+// NewSeeder returns a seeder whose RNG is a PCG seeded with seed
+func NewSeeder(seed uint64) *Seeder {
+	return &Seeder{Seed: seed, rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// NewSeederFromSource returns a seeder drawing from an arbitrary rand.Source (Seed is left 0)
+func NewSeederFromSource(src rand.Source) *Seeder {
+	return &Seeder{rng: rand.New(src)}
+}
+
+// RandomSeed returns a fresh seed (from the clock) for runs that did not ask for one
+func RandomSeed() uint64 {
+	return uint64(time.Now().UnixNano())
+}
+
+// perm is rand.Perm on the seeder's RNG
+func (s *Seeder) perm(n int) []int {
+	return s.rng.Perm(n)
+}
+
+// mark returns the mark array to use, allocating one for disjoint runs that did not provide it
+func (s *Seeder) mark(n int) []bool {
+	if s.Disjoint && s.Mark == nil {
+		s.Mark = make([]bool, n)
+	}
+	return s.Mark
+}
+
+// SelectSeeds1 picks one-hop stars (see SelectSeeds1); returns the number of distinct seeds in Disjoint mode
+func (s *Seeder) SelectSeeds1(G [][]int, seeds [][]int) int {
+	return selectSeeds1(G, seeds, s.mark(len(G)), s.Disjoint, s.perm)
+}
+
+// SelectSeeds2 picks two-hop stars (see SelectSeeds2); returns the number of distinct seeds in Disjoint mode
+func (s *Seeder) SelectSeeds2(G [][]int, seeds [][]int) int {
+	return selectSeeds2(G, seeds, s.mark(len(G)), s.Disjoint, s.perm)
+}
+
+// SelectSeeds3 picks three-hop stars (see SelectSeeds3); returns the number of distinct seeds in Disjoint mode
+func (s *Seeder) SelectSeeds3(G [][]int, seeds [][]int) int {
+	return selectSeeds3(G, seeds, s.mark(len(G)), s.Disjoint, s.perm)
+}
+
+// SelectSeeds2Heap picks two-hop stars with the lazy heap (see SelectSeeds2Heap)
+func (s *Seeder) SelectSeeds2Heap(G [][]int, seeds [][]int) int {
+	return selectSeedsHeap(G, seeds, 2, s.mark(len(G)), s.Disjoint, s.perm)
+}
+
+// SelectSeeds3Heap picks three-hop stars with the lazy heap (see SelectSeeds3Heap)
+func (s *Seeder) SelectSeeds3Heap(G [][]int, seeds [][]int) int {
+	return selectSeedsHeap(G, seeds, 3, s.mark(len(G)), s.Disjoint, s.perm)
+}
+
+// SelectTopDegree groups the highest-degree vertices into batches (see SelectTopDegree)
+func (s *Seeder) SelectTopDegree(G [][]int, seeds [][]int) int {
+	return selectTopDegree(G, seeds, s.mark(len(G)), s.Disjoint)
+}
+
+// SelectKCenter picks batches by farthest-point traversal (see SelectKCenter)
+func (s *Seeder) SelectKCenter(G [][]int, seeds [][]int) int {
+	return selectKCenter(G, seeds, s.mark(len(G)), s.Disjoint)
+}
+
+// SelectCoverage picks batches by greedy R-hop coverage (see SelectCoverage)
+func (s *Seeder) SelectCoverage(G [][]int, seeds [][]int, R int) int {
+	return selectCoverage(G, seeds, R, s.mark(len(G)), s.Disjoint)
+}
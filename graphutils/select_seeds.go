@@ -9,14 +9,34 @@ import (
 
 // One-hop star
 func SelectSeeds1(G [][]int, seeds [][]int) {
+	selectSeeds1(G, seeds, nil, false, rand.Perm)
+}
+
+// SelectSeeds1Masked is SelectSeeds1 that never picks a vertex v with exclude[v] set (nil excludes nothing)
+func SelectSeeds1Masked(G [][]int, seeds [][]int, exclude []bool) {
+	selectSeeds1(G, seeds, exclude, false, rand.Perm)
+}
+
+// SelectSeeds1Disjoint mirrors LandmarkLabeling_batch::select_seeds: every chosen seed is marked in the
+// shared mark array, so no vertex appears in two batches (or in two calls sharing mark; nil starts fresh).
+// Batches left over when candidates run out are filled with the sentinel n = len(G).
+// Returns the number of distinct seeds selected.
+func SelectSeeds1Disjoint(G [][]int, seeds [][]int, mark []bool) int {
+	if mark == nil {
+		mark = make([]bool, len(G))
+	}
+	return selectSeeds1(G, seeds, mark, true, rand.Perm)
+}
+
+func selectSeeds1(G [][]int, seeds [][]int, mark []bool, disjoint bool, perm func(int) []int) int {
 	n := len(G)
 	setSize := len(seeds[0]) // Number of seeds in each batch
-	// 1) make a random ordering of all vertices 0…n−1
-	ord := rand.Perm(n)
+	// 1) make a random ordering of all vertices 0…n−1 (global RNG, or the Seeder's own)
+	ord := perm(n)
 	// 2) filter high-degree (vertices whose degree ≥ set_size)
 	var verts []int
 	for _, v := range ord {
-		if len(G[v]) >= setSize {
+		if len(G[v]) >= setSize && !excluded(mark, v) {
 			verts = append(verts, v)
 		}
 	}
@@ -27,11 +47,19 @@ func SelectSeeds1(G [][]int, seeds [][]int) {
 		getOrder[v] = i
 	}
 
-	r := 0 // how many batches we’ve filled
+	r := 0      // how many batches we’ve filled
+	actual := 0 // how many seeds we’ve picked (padding excluded)
 	// build each batch
 	for _, v := range verts {
+		// in disjoint mode an earlier batch may already own this center
+		if disjoint && mark[v] {
+			continue
+		}
 		seeds[r][0] = v
 		ns := 1 // next free slot index
+		if disjoint {
+			mark[v] = true
+		}
 		// sort neighbors by getOrder[u] to impose the same randomness
 		neigh := append([]int(nil), G[v]...)
 		sort.Slice(neigh, func(i, j int) bool {
@@ -40,16 +68,20 @@ func SelectSeeds1(G [][]int, seeds [][]int) {
 			return getOrder[neigh[i]] < getOrder[neigh[j]]
 		})
 		for _, u := range neigh {
-			// skip self-loops
-			if u == v {
+			// skip self-loops and excluded (or already used) vertices
+			if u == v || excluded(mark, u) {
 				continue
 			}
 			seeds[r][ns] = u
 			ns++
+			if disjoint {
+				mark[u] = true
+			}
 			if ns == setSize { // If all slots are filled, stop
 				break
 			}
 		}
+		actual += ns
 		// pad if needed
 		for ns < setSize {
 			seeds[r][ns] = v
@@ -61,20 +93,42 @@ func SelectSeeds1(G [][]int, seeds [][]int) {
 			break
 		}
 	}
+	if disjoint {
+		fillSentinel(seeds, r, n)
+	}
+	return actual
 }
 
-/* TBD */
+/* TBD: materializes the whole 2-hop neighborhood; SelectSeeds2Heap is the bounded version */
 // Two-hop star
 func SelectSeeds2(G [][]int, seeds [][]int) {
+	selectSeeds2(G, seeds, nil, false, rand.Perm)
+}
+
+// SelectSeeds2Masked is SelectSeeds2 that never picks a vertex v with exclude[v] set (nil excludes nothing)
+func SelectSeeds2Masked(G [][]int, seeds [][]int, exclude []bool) {
+	selectSeeds2(G, seeds, exclude, false, rand.Perm)
+}
+
+// SelectSeeds2Disjoint is the disjoint (shared mark array, sentinel-padded) version of SelectSeeds2
+// Returns the number of distinct seeds selected.
+func SelectSeeds2Disjoint(G [][]int, seeds [][]int, mark []bool) int {
+	if mark == nil {
+		mark = make([]bool, len(G))
+	}
+	return selectSeeds2(G, seeds, mark, true, rand.Perm)
+}
+
+func selectSeeds2(G [][]int, seeds [][]int, mark []bool, disjoint bool, perm func(int) []int) int {
 	n := len(G)
 	setSize := len(seeds[0])
 	// 1) random permutation of all vertices
-	ord := rand.Perm(n)
+	ord := perm(n)
 	// 2) filter vertices with degree ≥ log(setSize)
 	threshold := int(math.Log(float64(setSize)))
 	var verts []int
 	for _, v := range ord {
-		if len(G[v]) >= threshold {
+		if len(G[v]) >= threshold && !excluded(mark, v) {
 			verts = append(verts, v)
 		}
 	}
@@ -85,19 +139,26 @@ func SelectSeeds2(G [][]int, seeds [][]int) {
 	}
 	// 4) for each center, collect up to setSize−1 from its 2-hop neighborhood
 	r := 0
+	actual := 0
 	for _, v := range verts {
+		if disjoint && mark[v] {
+			continue
+		}
 		seeds[r][0] = v
 		ns := 1
+		if disjoint {
+			mark[v] = true
+		}
 		// use a set to dedupe
 		seen := make(map[int]struct{}, setSize)
 		// 1-hop
 		for _, u := range G[v] {
-			if u != v {
+			if u != v && !excluded(mark, u) {
 				seen[u] = struct{}{}
 			}
 			// 2-hop
 			for _, w := range G[u] {
-				if w != v {
+				if w != v && !excluded(mark, w) {
 					seen[w] = struct{}{}
 				}
 			}
@@ -118,7 +179,11 @@ func SelectSeeds2(G [][]int, seeds [][]int) {
 			}
 			seeds[r][ns] = u
 			ns++
+			if disjoint {
+				mark[u] = true
+			}
 		}
+		actual += ns
 		// pad with center if needed
 		for ns < setSize {
 			seeds[r][ns] = v
@@ -129,17 +194,39 @@ func SelectSeeds2(G [][]int, seeds [][]int) {
 			break
 		}
 	}
+	if disjoint {
+		fillSentinel(seeds, r, n)
+	}
+	return actual
 }
 
 // Three-hop star
 func SelectSeeds3(G [][]int, seeds [][]int) {
+	selectSeeds3(G, seeds, nil, false, rand.Perm)
+}
+
+// SelectSeeds3Masked is SelectSeeds3 that never picks a vertex v with exclude[v] set (nil excludes nothing)
+func SelectSeeds3Masked(G [][]int, seeds [][]int, exclude []bool) {
+	selectSeeds3(G, seeds, exclude, false, rand.Perm)
+}
+
+// SelectSeeds3Disjoint is the disjoint (shared mark array, sentinel-padded) version of SelectSeeds3
+// Returns the number of distinct seeds selected.
+func SelectSeeds3Disjoint(G [][]int, seeds [][]int, mark []bool) int {
+	if mark == nil {
+		mark = make([]bool, len(G))
+	}
+	return selectSeeds3(G, seeds, mark, true, rand.Perm)
+}
+
+func selectSeeds3(G [][]int, seeds [][]int, mark []bool, disjoint bool, perm func(int) []int) int {
 	n := len(G)
 	setSize := len(seeds[0])
-	ord := rand.Perm(n)
+	ord := perm(n)
 	threshold := int(math.Log(float64(setSize)))
 	var verts []int
 	for _, v := range ord {
-		if len(G[v]) >= threshold {
+		if len(G[v]) >= threshold && !excluded(mark, v) {
 			verts = append(verts, v)
 		}
 	}
@@ -148,9 +235,16 @@ func SelectSeeds3(G [][]int, seeds [][]int) {
 		getOrder[v] = i
 	}
 	r := 0
+	actual := 0
 	for _, src := range verts {
+		if disjoint && mark[src] {
+			continue
+		}
 		seeds[r][0] = src
 		ns := 1
+		if disjoint {
+			mark[src] = true
+		}
 		// visited array for 3-hop
 		visited := make([]bool, n)
 		visited[src] = true
@@ -171,7 +265,7 @@ func SelectSeeds3(G [][]int, seeds [][]int) {
 		// collect all visited ≠ src
 		var neigh3 []int
 		for u, ok := range visited {
-			if ok && u != src {
+			if ok && u != src && !excluded(mark, u) {
 				neigh3 = append(neigh3, u)
 			}
 		}
@@ -186,7 +280,11 @@ func SelectSeeds3(G [][]int, seeds [][]int) {
 			}
 			seeds[r][ns] = u
 			ns++
+			if disjoint {
+				mark[u] = true
+			}
 		}
+		actual += ns
 		for ns < setSize {
 			seeds[r][ns] = src
 			ns++
@@ -196,4 +294,31 @@ func SelectSeeds3(G [][]int, seeds [][]int) {
 			break
 		}
 	}
+	if disjoint {
+		fillSentinel(seeds, r, n)
+	}
+	return actual
+}
+
+// excluded reports whether v is masked out of seed selection
+func excluded(exclude []bool, v int) bool {
+	return exclude != nil && exclude[v]
+}
+
+// fillSentinel fills batches r… with the sentinel n, like the C++ select_seeds when batches run out
+func fillSentinel(seeds [][]int, r, n int) {
+	for ; r < len(seeds); r++ {
+		for i := range seeds[r] {
+			seeds[r][i] = n
+		}
+	}
+}
+
+// TrimSentinel drops the trailing sentinel batches (those starting with n) so callers only see real batches
+func TrimSentinel(seeds [][]int, n int) [][]int {
+	r := 0
+	for r < len(seeds) && seeds[r][0] != n {
+		r++
+	}
+	return seeds[:r]
 }
@@ -0,0 +1,136 @@
+package graphutils
+
+import (
+	"container/heap"
+	"math"
+	"math/rand/v2"
+)
+
+// Lazy k-hop star selection, ported from LandmarkLabeling_batch::select_seeds2 (ADO_cluster.h)
+// Instead of materializing and sorting the whole 2-hop (or 3-hop) neighborhood of a center, a heap of
+// list headers (x, j) merges the neighbor lists G[x] and yields the earliest-ordered candidates one at a time.
+// At most setSize lists are ever opened per center, so the work per center is bounded by setSize pops
+// (plus skipped duplicates) instead of by the size of the neighborhood.
+
+// header points at G[x][j]; depth is the hop distance (through x) of the vertices in G[x]
+type header struct {
+	x, j, depth int
+}
+
+// headerHeap is a min-heap of headers ordered by getOrder of the vertex they point at
+type headerHeap struct {
+	G        [][]int
+	getOrder []int
+	items    []header
+}
+
+func (h *headerHeap) Len() int { return len(h.items) }
+func (h *headerHeap) Less(a, b int) bool {
+	ha, hb := h.items[a], h.items[b]
+	return h.getOrder[h.G[ha.x][ha.j]] < h.getOrder[h.G[hb.x][hb.j]]
+}
+func (h *headerHeap) Swap(a, b int)      { h.items[a], h.items[b] = h.items[b], h.items[a] }
+func (h *headerHeap) Push(x interface{}) { h.items = append(h.items, x.(header)) }
+func (h *headerHeap) Pop() interface{} {
+	it := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return it
+}
+
+// Two-hop star with a lazy heap (bounded version of SelectSeeds2)
+func SelectSeeds2Heap(G [][]int, seeds [][]int) {
+	selectSeedsHeap(G, seeds, 2, nil, false, rand.Perm)
+}
+
+// Three-hop star with a lazy heap (bounded version of SelectSeeds3)
+func SelectSeeds3Heap(G [][]int, seeds [][]int) {
+	selectSeedsHeap(G, seeds, 3, nil, false, rand.Perm)
+}
+
+func selectSeedsHeap(G [][]int, seeds [][]int, hops int, mark []bool, disjoint bool, perm func(int) []int) int {
+	n := len(G)
+	setSize := len(seeds[0])
+	// 1) random permutation of all vertices and its inverse
+	ord := perm(n)
+	getOrder := make([]int, n)
+	for i, v := range ord {
+		getOrder[v] = i
+	}
+	// 2) filter vertices with degree ≥ log(setSize)
+	threshold := int(math.Log(float64(setSize)))
+
+	r := 0
+	actual := 0
+	// taken dedupes within one batch when chosen seeds are not marked globally
+	taken := make(map[int]struct{}, setSize)
+	h := &headerHeap{G: G, getOrder: getOrder}
+	for _, v := range ord {
+		if len(G[v]) < threshold || excluded(mark, v) {
+			continue
+		}
+		seeds[r][0] = v
+		ns := 1
+		if disjoint {
+			mark[v] = true
+		}
+		clear(taken)
+		taken[v] = struct{}{}
+
+		// 3) headers: v's own list plus the lists of its first setSize−1 neighbors (as in the C++ code)
+		h.items = h.items[:0]
+		opened := 0
+		open := func(x, depth int) {
+			if len(G[x]) > 0 && opened < setSize {
+				h.items = append(h.items, header{x, 0, depth})
+				opened++
+			}
+		}
+		open(v, 1)
+		if hops >= 2 {
+			for i := 0; i < len(G[v]) && opened < setSize; i++ {
+				open(G[v][i], 2)
+			}
+		}
+		heap.Init(h)
+
+		// 4) pop candidates in getOrder until the batch is full
+		for h.Len() > 0 && ns < setSize {
+			top := h.items[0]
+			u := G[top.x][top.j]
+			if top.j+1 < len(G[top.x]) {
+				h.items[0].j++
+				heap.Fix(h, 0)
+			} else {
+				heap.Pop(h)
+			}
+			if _, ok := taken[u]; ok || excluded(mark, u) {
+				continue
+			}
+			taken[u] = struct{}{}
+			seeds[r][ns] = u
+			ns++
+			if disjoint {
+				mark[u] = true
+			}
+			// beyond two hops, a chosen 2-hop vertex opens its own list lazily
+			if top.depth >= 2 && top.depth < hops && opened < setSize && len(G[u]) > 0 {
+				opened++
+				heap.Push(h, header{u, 0, top.depth + 1})
+			}
+		}
+		actual += ns
+		// pad with center if needed
+		for ns < setSize {
+			seeds[r][ns] = v
+			ns++
+		}
+		r++
+		if r == len(seeds) {
+			break
+		}
+	}
+	if disjoint {
+		fillSentinel(seeds, r, n)
+	}
+	return actual
+}
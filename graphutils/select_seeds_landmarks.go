@@ -0,0 +1,232 @@
+package graphutils
+
+import "container/heap"
+
+// Landmark-style strategies: instead of random-center stars, pick batches that are good for the distance oracle.
+// All of them are deterministic (no RNG), never reuse a vertex across batches, and fill batches they cannot
+// build with the sentinel n like the disjoint star strategies. Each returns the number of distinct seeds.
+
+// nearestBatch fills batch with center followed by the closest eligible vertices in BFS order from center
+// (padding with center); used marks vertices already taken by earlier batches and is updated
+func nearestBatch(G [][]int, center int, batch []int, used []bool, visited []bool) int {
+	k := len(batch)
+	batch[0] = center
+	used[center] = true
+	ns := 1
+	visited[center] = true
+	queue := []int{center}
+	for head := 0; head < len(queue) && ns < k; head++ {
+		for _, w := range G[queue[head]] {
+			if visited[w] {
+				continue
+			}
+			visited[w] = true
+			queue = append(queue, w)
+			if !used[w] {
+				used[w] = true
+				batch[ns] = w
+				ns++
+				if ns == k {
+					break
+				}
+			}
+		}
+	}
+	for _, v := range queue {
+		visited[v] = false
+	}
+	for i := ns; i < k; i++ {
+		batch[i] = center
+	}
+	return ns
+}
+
+// usedMarks returns a copy of mark (or a fresh array) to track taken vertices when the caller did not ask
+// for its mark array to be updated
+func usedMarks(mark []bool, n int, disjoint bool) []bool {
+	if disjoint {
+		return mark
+	}
+	used := make([]bool, n)
+	copy(used, mark)
+	return used
+}
+
+// SelectTopDegree groups the highest-degree vertices into batches of consecutive degree rank:
+// batch i holds the (i·k)-th … ((i+1)·k−1)-th highest-degree eligible vertices
+func SelectTopDegree(G [][]int, seeds [][]int) int {
+	return selectTopDegree(G, seeds, nil, false)
+}
+
+func selectTopDegree(G [][]int, seeds [][]int, mark []bool, disjoint bool) int {
+	n := len(G)
+	used := usedMarks(mark, n, disjoint)
+	r, ns, actual := 0, 0, 0
+	for _, v := range OrderByDegrees(G) {
+		if r == len(seeds) {
+			break
+		}
+		if used[v] {
+			continue
+		}
+		used[v] = true
+		seeds[r][ns] = v
+		ns++
+		actual++
+		if ns == len(seeds[r]) {
+			r, ns = r+1, 0
+		}
+	}
+	// pad a partially filled last batch with its first seed
+	if ns > 0 {
+		for i := ns; i < len(seeds[r]); i++ {
+			seeds[r][i] = seeds[r][0]
+		}
+		r++
+	}
+	fillSentinel(seeds, r, n)
+	return actual
+}
+
+// SelectKCenter picks batch centers by farthest-point traversal (the greedy 2-approximation of k-center):
+// the first center is the highest-degree vertex, every next one is the vertex farthest (in hops) from all
+// previous centers. Unreachable vertices count as farthest, so combine with a component mask (MarkSmallComponents)
+// to stay inside the giant component. Each batch is its center plus its nearest eligible vertices.
+func SelectKCenter(G [][]int, seeds [][]int) int {
+	return selectKCenter(G, seeds, nil, false)
+}
+
+func selectKCenter(G [][]int, seeds [][]int, mark []bool, disjoint bool) int {
+	n := len(G)
+	used := usedMarks(mark, n, disjoint)
+	byDegree := OrderByDegrees(G)
+	const inf = int(^uint(0) >> 1)
+	dist := make([]int, n)
+	for i := range dist {
+		dist[i] = inf
+	}
+	visited := make([]bool, n)
+
+	r, actual := 0, 0
+	for ; r < len(seeds); r++ {
+		// farthest eligible vertex from the chosen centers (ties: higher degree first)
+		center, far := -1, -1
+		for _, v := range byDegree {
+			if !used[v] && dist[v] > far {
+				center, far = v, dist[v]
+			}
+		}
+		if center == -1 {
+			break
+		}
+		actual += nearestBatch(G, center, seeds[r], used, visited)
+		// pruned BFS: only vertices that get closer to the center set are revisited
+		dist[center] = 0
+		queue := []int{center}
+		for head := 0; head < len(queue); head++ {
+			u := queue[head]
+			for _, w := range G[u] {
+				if dist[u]+1 < dist[w] {
+					dist[w] = dist[u] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	fillSentinel(seeds, r, n)
+	return actual
+}
+
+// coverageItem is a candidate center with its (possibly stale) marginal gain
+type coverageItem struct {
+	v, gain, round int
+}
+
+type coverageHeap []coverageItem
+
+func (h coverageHeap) Len() int { return len(h) }
+func (h coverageHeap) Less(i, j int) bool {
+	if h[i].gain != h[j].gain {
+		return h[i].gain > h[j].gain
+	}
+	return h[i].v < h[j].v
+}
+func (h coverageHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *coverageHeap) Push(x interface{}) { *h = append(*h, x.(coverageItem)) }
+func (h *coverageHeap) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// SelectCoverage greedily picks batches that maximize the number of not-yet-covered vertices within R hops
+// of the batch. Candidate centers are the 8·ns+64 highest-degree vertices; each candidate's batch is the center
+// plus its nearest eligible vertices. Gains only shrink as coverage grows (up to changes in batch membership),
+// so the greedy is evaluated lazily: a candidate is re-scored only when it reaches the top of the heap
+// with a gain computed in an earlier round.
+func SelectCoverage(G [][]int, seeds [][]int, R int) int {
+	return selectCoverage(G, seeds, R, nil, false)
+}
+
+func selectCoverage(G [][]int, seeds [][]int, R int, mark []bool, disjoint bool) int {
+	n := len(G)
+	used := usedMarks(mark, n, disjoint)
+	covered := make([]bool, n)
+	visited := make([]bool, n)
+	scratch := make([]bool, n)
+	k := len(seeds[0])
+	batch := make([]int, k)
+
+	// score builds v's batch without committing it and counts the uncovered vertices of its R-ball
+	score := func(v int) int {
+		ns := nearestBatch(G, v, batch, used, visited)
+		for _, u := range batch[:ns] {
+			used[u] = false // undo: nearestBatch marks what it takes
+		}
+		gain := 0
+		for _, u := range kHopBallInto(G, batch[:ns], R, scratch) {
+			if !covered[u] {
+				gain++
+			}
+		}
+		return gain
+	}
+
+	pool := 8*len(seeds) + 64
+	h := &coverageHeap{}
+	for _, v := range OrderByDegrees(G) {
+		if h.Len() == pool {
+			break
+		}
+		if !used[v] {
+			*h = append(*h, coverageItem{v, n + 1, -1}) // optimistic gain: scored on first pop
+		}
+	}
+	heap.Init(h)
+
+	r, actual := 0, 0
+	for r < len(seeds) && h.Len() > 0 {
+		top := heap.Pop(h).(coverageItem)
+		if used[top.v] {
+			continue
+		}
+		if top.round != r {
+			// stale: re-score and put back
+			top.gain, top.round = score(top.v), r
+			heap.Push(h, top)
+			continue
+		}
+		if top.gain == 0 {
+			break // nothing left to cover
+		}
+		ns := nearestBatch(G, top.v, seeds[r], used, visited)
+		actual += ns
+		for _, u := range kHopBallInto(G, seeds[r][:ns], R, scratch) {
+			covered[u] = true
+		}
+		r++
+	}
+	fillSentinel(seeds, r, n)
+	return actual
+}
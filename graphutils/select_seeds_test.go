@@ -0,0 +1,190 @@
+package graphutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDisjointSeedsNeverRepeat(t *testing.T) {
+	G := BuildAdjFromCSR(Kronecker(9, 8, 5))
+	n := len(G)
+	for name, sel := range map[string]func([][]int, []bool) int{
+		"one-hop":   func(s [][]int, m []bool) int { return SelectSeeds1Disjoint(G, s, m) },
+		"two-hop":   func(s [][]int, m []bool) int { return SelectSeeds2Disjoint(G, s, m) },
+		"three-hop": func(s [][]int, m []bool) int { return SelectSeeds3Disjoint(G, s, m) },
+	} {
+		seeds := make([][]int, 1000) // far more batches than the graph can fill
+		for i := range seeds {
+			seeds[i] = make([]int, 8)
+		}
+		actual := sel(seeds, nil)
+
+		used := map[int]int{}
+		distinct := 0
+		for b, batch := range seeds {
+			if batch[0] == n {
+				for _, v := range batch {
+					if v != n {
+						t.Fatalf("%s: sentinel batch %d contains %d", name, b, v)
+					}
+				}
+				continue
+			}
+			for i, v := range batch {
+				if i > 0 && v == batch[0] {
+					continue // padding
+				}
+				if prev, ok := used[v]; ok {
+					t.Fatalf("%s: vertex %d in batches %d and %d", name, v, prev, b)
+				}
+				used[v] = b
+				distinct++
+			}
+		}
+		if distinct != actual {
+			t.Fatalf("%s: returned %d seeds, found %d", name, actual, distinct)
+		}
+		if trimmed := TrimSentinel(seeds, n); len(trimmed) == len(seeds) {
+			t.Fatalf("%s: expected sentinel batches to be trimmed", name)
+		}
+	}
+}
+
+func TestSeederIsReproducible(t *testing.T) {
+	G := BuildAdjFromCSR(Kronecker(9, 8, 5))
+	pick := func(seed uint64) [][]int {
+		seeds := make([][]int, 4)
+		for i := range seeds {
+			seeds[i] = make([]int, 8)
+		}
+		NewSeeder(seed).SelectSeeds2(G, seeds)
+		return seeds
+	}
+	a, b, c := pick(11), pick(11), pick(12)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("same seed, different batches:\n%v\n%v", a, b)
+	}
+	if reflect.DeepEqual(a, c) {
+		t.Fatal("different seeds picked identical batches")
+	}
+}
+
+func TestHeapSeedsStayWithinHops(t *testing.T) {
+	G := BuildAdjFromCSR(Grid2D(20, 20))
+	for hops, sel := range map[int]func([][]int) int{
+		2: func(s [][]int) int { return NewSeeder(1).SelectSeeds2Heap(G, s) },
+		3: func(s [][]int) int { return NewSeeder(1).SelectSeeds3Heap(G, s) },
+	} {
+		seeds := make([][]int, 5)
+		for i := range seeds {
+			seeds[i] = make([]int, 8)
+		}
+		sel(seeds)
+		for _, batch := range seeds {
+			ball := map[int]bool{}
+			for _, v := range KHopBall(G, batch[:1], hops) {
+				ball[v] = true
+			}
+			seen := map[int]bool{}
+			for i, v := range batch {
+				if !ball[v] {
+					t.Fatalf("%d-hop: %d is farther than %d hops from center %d", hops, v, hops, batch[0])
+				}
+				if i > 0 && v != batch[0] && seen[v] {
+					t.Fatalf("%d-hop: %d repeated in batch %v", hops, v, batch)
+				}
+				seen[v] = true
+			}
+		}
+	}
+}
+
+func TestLandmarkStrategies(t *testing.T) {
+	G := BuildAdjFromCSR(Grid2D(30, 30))
+	n := len(G)
+	newSeeds := func() [][]int {
+		seeds := make([][]int, 6)
+		for i := range seeds {
+			seeds[i] = make([]int, 4)
+		}
+		return seeds
+	}
+
+	top := newSeeds()
+	if got := SelectTopDegree(G, top); got != 24 {
+		t.Fatalf("top-degree picked %d seeds, want 24", got)
+	}
+	for _, batch := range top {
+		for _, v := range batch {
+			if len(G[v]) != 4 {
+				t.Fatalf("top-degree picked %d with degree %d", v, len(G[v]))
+			}
+		}
+	}
+
+	// farthest-point: the second center must be a corner far away from the first
+	kc := newSeeds()
+	SelectKCenter(G, kc)
+	a, b := kc[0][0], kc[1][0]
+	if d := abs(a/30-b/30) + abs(a%30-b%30); d < 30 {
+		t.Fatalf("k-center centers %d and %d are only %d apart", a, b, d)
+	}
+
+	// coverage never picks overlapping batches when there is room to spread out
+	cov := newSeeds()
+	SelectCoverage(G, cov, 2)
+	seen := map[int]bool{}
+	for _, batch := range TrimSentinel(cov, n) {
+		for _, v := range KHopBall(G, batch, 2) {
+			seen[v] = true
+		}
+	}
+	if len(seen) < 6*20 {
+		t.Fatalf("coverage batches only cover %d vertices", len(seen))
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func TestRegisteredSeedSelectors(t *testing.T) {
+	G := BuildAdjFromCSR(Kronecker(9, 8, 5))
+	n := len(G)
+	for _, name := range SeedStrategyNames() {
+		for _, disjoint := range []bool{false, true} {
+			sel, err := NewSeedSelector(name, SeedOptions{Seed: 3, Disjoint: disjoint, R: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sb, err := sel.Select(G, 6, 8)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if len(sb.Batches) != 6 || len(sb.Used()) != 6-sb.Unused {
+				t.Fatalf("%s: %d batches, %d unused", name, len(sb.Batches), sb.Unused)
+			}
+			slots := 0
+			for _, batch := range sb.Used() {
+				if len(batch) != 8 {
+					t.Fatalf("%s: batch of size %d", name, len(batch))
+				}
+				for _, v := range batch {
+					if v < 0 || v >= n {
+						t.Fatalf("%s: vertex %d out of range in a used batch", name, v)
+					}
+				}
+				slots += len(batch)
+			}
+			if sb.Actual+sb.Padding != slots {
+				t.Fatalf("%s (disjoint=%v): actual %d + padding %d != %d slots", name, disjoint, sb.Actual, sb.Padding, slots)
+			}
+		}
+	}
+	if _, err := NewSeedSelector("nope", SeedOptions{}); err == nil {
+		t.Fatal("expected an error for an unknown strategy")
+	}
+}
@@ -0,0 +1,90 @@
+package graphutils
+
+import "sort"
+
+// Subgraph is an induced subgraph of a larger graph with compact vertex IDs 0…len(Orig)−1
+type Subgraph struct {
+	Offsets []uint64
+	Edges   []uint32
+	Orig    []int       // Orig[i]: ID in the original graph of subgraph vertex i
+	NewID   map[int]int // NewID[v]: ID in the subgraph of original vertex v (only for kept vertices)
+}
+
+// InducedSubgraph keeps the given vertices (in the given order, duplicates ignored) and every edge between them
+func InducedSubgraph(G [][]int, vertices []int) *Subgraph {
+	sub := &Subgraph{NewID: make(map[int]int, len(vertices))}
+	for _, v := range vertices {
+		if _, ok := sub.NewID[v]; ok {
+			continue
+		}
+		sub.NewID[v] = len(sub.Orig)
+		sub.Orig = append(sub.Orig, v)
+	}
+
+	// Build CSR: keep only neighbors that are inside the vertex set, sorted by their new IDs
+	sub.Offsets = make([]uint64, len(sub.Orig)+1)
+	for i, v := range sub.Orig {
+		start := len(sub.Edges)
+		for _, u := range G[v] {
+			if id, ok := sub.NewID[u]; ok {
+				sub.Edges = append(sub.Edges, uint32(id))
+			}
+		}
+		nbrs := sub.Edges[start:]
+		sort.Slice(nbrs, func(a, b int) bool { return nbrs[a] < nbrs[b] })
+		sub.Offsets[i+1] = uint64(len(sub.Edges))
+	}
+	return sub
+}
+
+// KHopBall returns every vertex within k hops of any source, in BFS order (sources first)
+// It is the same region SelectSeeds3 explores with its visited array
+func KHopBall(G [][]int, sources []int, k int) []int {
+	return kHopBallInto(G, sources, k, make([]bool, len(G)))
+}
+
+// kHopBallInto is KHopBall with a caller-provided all-false visited array, which is all false again on return
+// (so seed selection can grow thousands of small balls without allocating n bools each time)
+func kHopBallInto(G [][]int, sources []int, k int, visited []bool) []int {
+	var ball []int
+	for _, s := range sources {
+		if !visited[s] {
+			visited[s] = true
+			ball = append(ball, s)
+		}
+	}
+	frontier := ball
+	// expand k times
+	for hop := 0; hop < k && len(frontier) > 0; hop++ {
+		start := len(ball)
+		for _, u := range frontier {
+			for _, w := range G[u] {
+				if !visited[w] {
+					visited[w] = true
+					ball = append(ball, w)
+				}
+			}
+		}
+		frontier = ball[start:]
+	}
+	for _, v := range ball {
+		visited[v] = false
+	}
+	return ball
+}
+
+// ExtractKHop extracts the subgraph induced by the k-hop ball around sources
+// Sources keep the smallest new IDs, in order, so a seed batch maps to 0…len(sources)−1
+func ExtractKHop(G [][]int, sources []int, k int) *Subgraph {
+	return InducedSubgraph(G, KHopBall(G, sources, k))
+}
+
+// Adj returns the subgraph as an adjacency list
+func (sub *Subgraph) Adj() [][]int {
+	return BuildAdjFromCSR(sub.Offsets, sub.Edges)
+}
+
+// WriteBin saves the subgraph in the .bin format read by ReadGraphFromBin
+func (sub *Subgraph) WriteBin(path string) error {
+	return WriteGraphToBin(path, sub.Offsets, sub.Edges)
+}
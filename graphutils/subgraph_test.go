@@ -0,0 +1,38 @@
+package graphutils
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractKHop(t *testing.T) {
+	G := BuildAdjFromCSR(PathGraph(10))
+	sub := ExtractKHop(G, []int{5}, 2)
+	if !reflect.DeepEqual(sub.Orig, []int{5, 4, 6, 3, 7}) {
+		t.Fatalf("ball = %v", sub.Orig)
+	}
+	H := sub.Adj()
+	// 3-4-5-6-7 is still a path: the two ends have degree 1
+	if len(H[sub.NewID[3]]) != 1 || len(H[sub.NewID[7]]) != 1 || len(H[sub.NewID[5]]) != 2 {
+		t.Fatalf("unexpected subgraph %v", H)
+	}
+	if !IsSymmetric(H) {
+		t.Fatalf("induced subgraph of a symmetric graph should be symmetric: %v", H)
+	}
+}
+
+func TestWriteBinRoundTrip(t *testing.T) {
+	offs, edges := Grid2D(3, 4)
+	path := filepath.Join(t.TempDir(), "grid.bin")
+	if err := WriteGraphToBin(path, offs, edges); err != nil {
+		t.Fatal(err)
+	}
+	offs2, edges2, err := ReadGraphFromBin(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(offs, offs2) || !reflect.DeepEqual(edges, edges2) {
+		t.Fatal("graph changed after a write/read round trip")
+	}
+}
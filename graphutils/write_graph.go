@@ -0,0 +1,40 @@
+package graphutils
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// WriteGraphToBin writes a CSR in the format read by ReadGraphFromBin
+/*
+Data format:
+n (uint64)
+m (uint64)
+sizes (uint64)
+offsets[0…n] ( (n+1)×uint64 )
+edgeIDs[0…m-1] ( m×uint32 )
+*/
+func WriteGraphToBin(path string, offsets []uint64, edges []uint32) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	n := uint64(len(offsets) - 1)
+	m := uint64(len(edges))
+	sizes := (n+1)*8 + m*4 + 3*8
+	for _, x := range []interface{}{n, m, sizes, offsets, edges} {
+		if err = binary.Write(w, binary.LittleEndian, x); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"cluster_bfs_go/cbfspb"
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// jobChunk is the number of vertex labels per RunJob message
+const jobChunk = 1024
+
+// grpcServer implements cbfspb.ClusterBFSServer on top of the HTTP server's index and graph
+type grpcServer struct {
+	cbfspb.UnimplementedClusterBFSServer
+	s *distanceServer
+}
+
+// newGRPCServer returns a gRPC server with the ClusterBFS service registered and its latency recorded in s.metrics
+func newGRPCServer(s *distanceServer) *grpc.Server {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		defer func() { s.metrics.observeLatency(info.FullMethod, time.Since(start)) }()
+		return h(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+		start := time.Now()
+		defer func() { s.metrics.observeLatency(info.FullMethod, time.Since(start)) }()
+		return h(srv, ss)
+	}
+	gs := grpc.NewServer(grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+	cbfspb.RegisterClusterBFSServer(gs, &grpcServer{s: s})
+	return gs
+}
+
+// distanceResponse wraps an answer of distanceServer.distance (nil d: unreachable)
+func distanceResponse(u, v int64, d *uint64, exact bool) *cbfspb.DistanceResponse {
+	resp := &cbfspb.DistanceResponse{U: u, V: v}
+	if d != nil {
+		resp.Reachable, resp.Distance, resp.Exact = true, *d, exact
+	}
+	return resp
+}
+
+func (g *grpcServer) Distance(ctx context.Context, req *cbfspb.DistanceRequest) (*cbfspb.DistanceResponse, error) {
+	for _, x := range []int64{req.U, req.V} {
+		if _, ok := g.s.oracle.Local(int(x)); !ok {
+			return nil, status.Errorf(codes.NotFound, "vertex %d is not in the index", x)
+		}
+	}
+	d, exact := g.s.distance(int(req.U), int(req.V))
+	return distanceResponse(req.U, req.V, d, exact), nil
+}
+
+func (g *grpcServer) BatchDistance(ctx context.Context, req *cbfspb.BatchDistanceRequest) (*cbfspb.BatchDistanceResponse, error) {
+	if len(req.Pairs) > g.s.maxBatch {
+		return nil, status.Errorf(codes.ResourceExhausted, "%d pairs in one request, the limit is %d", len(req.Pairs), g.s.maxBatch)
+	}
+	pairs := make([][2]int, len(req.Pairs))
+	for i, p := range req.Pairs {
+		pairs[i] = [2]int{int(p.U), int(p.V)}
+	}
+	ds, exact := g.s.distances(pairs)
+	resp := &cbfspb.BatchDistanceResponse{Results: make([]*cbfspb.DistanceResponse, len(req.Pairs))}
+	for i, p := range req.Pairs {
+		resp.Results[i] = distanceResponse(p.U, p.V, ds[i], exact[i])
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) RunJob(req *cbfspb.JobRequest, stream cbfspb.ClusterBFS_RunJobServer) error {
+	// 1) Check the request and map the seeds to the graph's IDs
+	R := int(req.R)
+	if R < 1 || R > g.s.meta.MaxRadius {
+		return status.Errorf(codes.InvalidArgument, "r=%d: want 1 to %d", req.R, g.s.meta.MaxRadius)
+	}
+	if len(req.Seeds) == 0 || len(req.Seeds) > 64 {
+		return status.Errorf(codes.InvalidArgument, "%d seeds: want 1 to 64", len(req.Seeds))
+	}
+	seeds := make([]int, len(req.Seeds))
+	seen := map[int64]bool{}
+	for i, v := range req.Seeds {
+		l, ok := g.s.oracle.Local(int(v))
+		if !ok {
+			return status.Errorf(codes.NotFound, "seed %d is not in the graph", v)
+		}
+		// Init would stop at a repeated seed, and bit j must stay seeds[j]
+		if seen[v] {
+			return status.Errorf(codes.InvalidArgument, "seed %d given twice", v)
+		}
+		seen[v] = true
+		seeds[i] = l
+	}
+
+	// 2) Run ClusterBFS, one job at a time; it stops when the client goes away or after -jobtimeout
+	ctx := stream.Context()
+	if g.s.jobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.s.jobTimeout)
+		defer cancel()
+	}
+	select {
+	case g.s.jobs <- struct{}{}:
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	cbfs := &ClusterBFS{G: g.s.G, GT: g.s.GT, R: R}
+	err := cbfs.RunCBFSContext(ctx, cbfs.Init(seeds))
+	<-g.s.jobs
+	if err != nil {
+		return status.Error(status.FromContextError(ctx.Err()).Code(), err.Error())
+	}
+
+	// 3) Stream the labels of the reached vertices in chunks
+	chunk := &cbfspb.JobResponse{}
+	for v, d := range cbfs.D {
+		if d == cbfs.INF {
+			continue
+		}
+		chunk.Labels = append(chunk.Labels, &cbfspb.VertexLabel{Vertex: int64(g.s.toInput[v]), D: d, S: cbfs.S[v]})
+		if len(chunk.Labels) == jobChunk {
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			chunk = &cbfspb.JobResponse{}
+		}
+	}
+	if len(chunk.Labels) > 0 {
+		return stream.Send(chunk)
+	}
+	return nil
+}
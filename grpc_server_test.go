@@ -0,0 +1,122 @@
+package main
+
+import (
+	"cluster_bfs_go/cbfspb"
+	"cluster_bfs_go/client"
+	"cluster_bfs_go/graphutils"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// The gRPC service in process over bufconn, on a 5x5 grid whose labels use the input IDs
+func TestGRPCServer(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.Grid2D(5, 5))
+	oracle := BuildOracle(G, G, [][]int{{0, 1, 5}, {24, 23, 19}}, 3)
+	toLocal := make([]int, len(G))
+	for v := range toLocal {
+		toLocal[v] = v
+	}
+	srv, err := newDistanceServer(oracle, &loadedGraph{G: G, GT: G, ToLocal: toLocal, Identity: true}, client.Metadata{MaxRadius: 4}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	gs := newGRPCServer(srv)
+	go gs.Serve(lis)
+	defer gs.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := cbfspb.NewClusterBFSClient(conn)
+	ctx := context.Background()
+
+	d, err := c.Distance(ctx, &cbfspb.DistanceRequest{U: 0, V: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Reachable || d.Distance != oracle.Query(0, 24) {
+		t.Fatalf("Distance(0, 24) = %v, want %d", d, oracle.Query(0, 24))
+	}
+	if _, err := c.Distance(ctx, &cbfspb.DistanceRequest{U: 0, V: 25}); status.Code(err) != codes.NotFound {
+		t.Fatalf("vertex outside the index: got %v, want NotFound", err)
+	}
+
+	batch, err := c.BatchDistance(ctx, &cbfspb.BatchDistanceRequest{Pairs: []*cbfspb.DistanceRequest{{U: 0, V: 24}, {U: 7, V: 7}, {U: 3, V: -1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := batch.Results; len(r) != 3 || r[0].Distance != d.Distance || !r[1].Reachable || r[1].Distance != 0 || r[2].Reachable {
+		t.Fatalf("unexpected batch answer %v", r)
+	}
+
+	// the streamed labels must be those of a local ClusterBFS run
+	seeds := []int64{12, 0, 7}
+	stream, err := c.RunJob(ctx, &cbfspb.JobRequest{Seeds: seeds, R: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ClusterBFS{G: G, GT: G, R: 2}
+	want.RunCBFS(want.Init([]int{12, 0, 7}))
+	got := 0
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, l := range resp.Labels {
+			if l.D != want.D[l.Vertex] || !slices.Equal(l.S, want.S[l.Vertex]) {
+				t.Fatalf("vertex %d: got D=%d S=%v, want D=%d S=%v", l.Vertex, l.D, l.S, want.D[l.Vertex], want.S[l.Vertex])
+			}
+			got++
+		}
+	}
+	if got != len(G) {
+		t.Fatalf("got labels for %d vertices, want %d", got, len(G))
+	}
+
+	for _, tc := range []struct {
+		req  *cbfspb.JobRequest
+		code codes.Code
+	}{
+		{&cbfspb.JobRequest{Seeds: seeds, R: 5}, codes.InvalidArgument},
+		{&cbfspb.JobRequest{R: 2}, codes.InvalidArgument},
+		{&cbfspb.JobRequest{Seeds: []int64{1, 2, 1}, R: 2}, codes.InvalidArgument},
+		{&cbfspb.JobRequest{Seeds: []int64{99}, R: 2}, codes.NotFound},
+	} {
+		stream, err := c.RunJob(ctx, tc.req)
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != tc.code {
+			t.Errorf("RunJob(%v): got %v, want %v", tc.req, err, tc.code)
+		}
+	}
+
+	// a job past -jobtimeout is cut short
+	srv.jobTimeout = time.Nanosecond
+	stream, err = c.RunJob(ctx, &cbfspb.JobRequest{Seeds: seeds, R: 2})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("RunJob past -jobtimeout: got %v, want %v", err, codes.DeadlineExceeded)
+	}
+}
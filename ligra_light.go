@@ -3,6 +3,7 @@ package main
 // analogue to Parlay's parallel loops
 import (
 	"cluster_bfs_go/parlay_go"
+	"context"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -200,7 +201,8 @@ func (em *EdgeMap[E]) f(u, v int, e E, backwards bool) bool {
 // edgeMapSparseParallel parallelizes across CPU workers while preserving
 // input order: it splits the vertices list into chunks, each worker
 // processes its range in order, collecting targets per u in adjacency order.
-func (em *EdgeMap[E]) edgeMapSparse(vertices []int) []int {
+// Workers stop early once done is closed, keeping the targets found so far.
+func (em *EdgeMap[E]) edgeMapSparse(done <-chan struct{}, vertices []int) []int {
 	n := len(vertices)
 
 	// Determine number of parallel workers (<= len(vertices))
@@ -237,6 +239,10 @@ func (em *EdgeMap[E]) edgeMapSparse(vertices []int) []int {
 			var localFlat []int
 			// Process vertices[s:e] in original input order
 			for i := s; i < e; i++ {
+				// check for cancellation every 1024 vertices
+				if (i-s)&1023 == 0 && canceled(done) {
+					break
+				}
 				u := vertices[i]
 				// Traverse G[u] in deterministic adjacency order
 				for _, edge := range em.G[u] {
@@ -268,7 +274,8 @@ func (em *EdgeMap[E]) edgeMapSparse(vertices []int) []int {
 // is true, it checks all its in-edges in GT[v]. Depending on the
 // exitEarly flag, it either stops at the first matching edge or
 // aggregates over all edges using a logical OR.
-func (em *EdgeMap[E]) edgeMapDense(vertices []bool, exitEarly bool) []bool {
+// Once done is closed, the remaining vertices are skipped (left false).
+func (em *EdgeMap[E]) edgeMapDense(done <-chan struct{}, vertices []bool, exitEarly bool) []bool {
 	// Allocate the output slice result with one entry per vertex, all initialized to false
 	result := make([]bool, em.n)
 	var wg sync.WaitGroup
@@ -279,6 +286,10 @@ func (em *EdgeMap[E]) edgeMapDense(vertices []bool, exitEarly bool) []bool {
 		go func(v int) {
 			defer wg.Done()
 
+			// Skip the vertex once the traversal is cancelled
+			if canceled(done) {
+				return
+			}
 			// Pre-filter on the vertex
 			if !em.cond(v) {
 				result[v] = false
@@ -384,50 +395,61 @@ func countTrue(b []bool) int {
 // It decides whether to use the sparse or dense method based on the size
 // of the input vertex subset and then returns a new VertexSubset as result.
 func (em *EdgeMap[E]) Run(vs VertexSubset, exitEarly bool) VertexSubset {
-	  // parallel count of active vertices
-    var activeCount int
-    if vs.isSparse {
-        activeCount = len(vs.sparse)
-    } else {
-        activeCount = countTrue(vs.dense)
-    }
-
-    if vs.isSparse {
-        // parallel compute incident edges count
-        var wg sync.WaitGroup
-        ch := make(chan int, len(vs.sparse))
-        for _, v := range vs.sparse {
-            wg.Add(1)
-            go func(v int) {
-                defer wg.Done()
-                ch <- len(em.G[v])
-            }(v)
-        }
-        go func() {
-            wg.Wait()
-            close(ch)
-        }()
-        d := 0
-        for cnt := range ch {
-            d += cnt
-        }
-        if (activeCount + d) > int(em.m/10) {
-            dVertices := make([]bool, em.n)
-            for _, i := range vs.sparse {
-                dVertices[i] = true
-            }
-            newDense := em.edgeMapDense(dVertices, exitEarly)
-            return NewDense(newDense)
-        }
-        newSparse := em.edgeMapSparse(vs.sparse)
-        return NewSparse(newSparse)
-    } else {
-        if activeCount > em.n/20 {
-            newDense := em.edgeMapDense(vs.dense, exitEarly)
-            return NewDense(newDense)
-        }
-        seq := vs.ToSeq()
-        newSparse := em.edgeMapSparse(seq)
-        return NewSparse(newSparse)
-    }
+	out, _ := em.RunContext(context.Background(), vs, exitEarly) // never cancelled
+	return out
+}
+
+// RunContext is Run with cancellation: once ctx is done the workers stop and it returns the part of the
+// output frontier found so far with a *PartialResultError
+func (em *EdgeMap[E]) RunContext(ctx context.Context, vs VertexSubset, exitEarly bool) (VertexSubset, error) {
+	done := ctx.Done()
+	// parallel count of active vertices
+	var activeCount int
+	if vs.isSparse {
+		activeCount = len(vs.sparse)
+	} else {
+		activeCount = countTrue(vs.dense)
+	}
+
+	var out VertexSubset
+	if vs.isSparse {
+		// parallel compute incident edges count
+		var wg sync.WaitGroup
+		ch := make(chan int, len(vs.sparse))
+		for _, v := range vs.sparse {
+			wg.Add(1)
+			go func(v int) {
+				defer wg.Done()
+				ch <- len(em.G[v])
+			}(v)
+		}
+		go func() {
+			wg.Wait()
+			close(ch)
+		}()
+		d := 0
+		for cnt := range ch {
+			d += cnt
+		}
+		if (activeCount + d) > int(em.m/10) {
+			dVertices := make([]bool, em.n)
+			for _, i := range vs.sparse {
+				dVertices[i] = true
+			}
+			out = NewDense(em.edgeMapDense(done, dVertices, exitEarly))
+		} else {
+			out = NewSparse(em.edgeMapSparse(done, vs.sparse))
+		}
+	} else {
+		if activeCount > em.n/20 {
+			out = NewDense(em.edgeMapDense(done, vs.dense, exitEarly))
+		} else {
+			seq := vs.ToSeq()
+			out = NewSparse(em.edgeMapSparse(done, seq))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return out, &PartialResultError{Op: "EdgeMap", Unit: "vertices", Completed: out.Size(), Err: err}
+	}
+	return out, nil
 }
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"cluster_bfs_go/graphutils"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestVertexSubset(t *testing.T) {
+	vs := NewEmptySparse()
+	vs.AddVertices([]int{3, 1})
+	vs.AddVertices([]int{4})
+	if vs.Size() != 3 || !slices.Equal(vs.ToSeq(), []int{3, 1, 4}) {
+		t.Fatalf("sparse subset: size %d, %v", vs.Size(), vs.ToSeq())
+	}
+	single := NewSingle(7)
+	if single.Size() != 1 || !slices.Equal(single.ToSeq(), []int{7}) {
+		t.Fatalf("single subset: %v", single.ToSeq())
+	}
+	dense := NewDense([]bool{false, true, false, true, true})
+	if dense.Size() != 3 || !slices.Equal(dense.ToSeq(), []int{1, 3, 4}) {
+		t.Fatalf("dense subset: size %d, %v", dense.Size(), dense.ToSeq())
+	}
+	dense.AddVertices([]int{0})
+	if dense.Size() != 4 || !slices.Equal(dense.ToSeq(), []int{0, 1, 3, 4}) {
+		t.Fatalf("dense subset after AddVertices: %v", dense.ToSeq())
+	}
+	// Apply visits every member exactly once in both representations
+	for _, s := range []VertexSubset{vs, dense} {
+		var sum int64
+		s.Apply(func(v int) { atomic.AddInt64(&sum, int64(v)) })
+		want := 0
+		for _, v := range s.ToSeq() {
+			want += v
+		}
+		if sum != int64(want) {
+			t.Fatalf("Apply sum %d, want %d", sum, want)
+		}
+	}
+	if got := countTrue(nil); got != 0 {
+		t.Fatalf("countTrue(nil) = %d", got)
+	}
+}
+
+// bfsStep is one level of BFS: the unvisited out-neighbors of frontier
+func bfsStep(G [][]int, frontier []int, visited []bool) []int {
+	seen := slices.Clone(visited)
+	var next []int
+	for _, u := range frontier {
+		for _, v := range G[u] {
+			if !seen[v] {
+				seen[v] = true
+				next = append(next, v)
+			}
+		}
+	}
+	slices.Sort(next)
+	return next
+}
+
+// EdgeMap must produce the same next frontier whether it runs sparse or dense
+func TestEdgeMapSparseAndDense(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.Kronecker(9, 6, 3))
+	GT := graphutils.TransposeAdj(G)
+	n := len(G)
+	rng := rand.New(rand.NewPCG(5, 6))
+	for _, size := range []int{1, 4, n / 2, n} {
+		frontier := rng.Perm(n)[:size]
+		visited := make([]bool, n)
+		for _, v := range frontier {
+			visited[v] = true
+		}
+		want := bfsStep(G, frontier, visited)
+
+		run := func(name string, step func(em *EdgeMap[int]) []int) {
+			claimed := make([]int32, n)
+			em := NewEdgeMap(G, GT,
+				func(u, v int, e int, backwards bool) bool {
+					return atomic.CompareAndSwapInt32(&claimed[v], 0, 1)
+				},
+				func(v int) bool { return !visited[v] },
+				Identity[int],
+			)
+			got := step(em)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Fatalf("%s, frontier %d: got %d vertices, want %d", name, size, len(got), len(want))
+			}
+		}
+		run("sparse", func(em *EdgeMap[int]) []int { return em.edgeMapSparse(nil, frontier) })
+		run("dense", func(em *EdgeMap[int]) []int {
+			out := NewDense(em.edgeMapDense(nil, denseOf(frontier, n), false))
+			return out.ToSeq()
+		})
+		run("Run(sparse input)", func(em *EdgeMap[int]) []int {
+			out := em.Run(NewSparse(frontier), false)
+			return out.ToSeq()
+		})
+		run("Run(dense input)", func(em *EdgeMap[int]) []int {
+			out := em.Run(NewDense(denseOf(frontier, n)), false)
+			return out.ToSeq()
+		})
+	}
+}
+
+// denseOf turns a vertex list into a dense membership array
+func denseOf(vs []int, n int) []bool {
+	dense := make([]bool, n)
+	for _, v := range vs {
+		dense[v] = true
+	}
+	return dense
+}
+
+// BFS-style EdgeMap step: a vertex is claimed once per run by stamping it with the run number
+func BenchmarkEdgeMapRun(b *testing.B) {
+	G := benchGraph()
+	n := len(G)
+	stamp := make([]uint64, n)
+	var run uint64
+	em := NewEdgeMap(G, G,
+		func(u, v int, e int, backwards bool) bool {
+			old := atomic.LoadUint64(&stamp[v])
+			return old != run && atomic.CompareAndSwapUint64(&stamp[v], old, run)
+		},
+		func(v int) bool { return true },
+		Identity[int],
+	)
+	// sparse: a handful of low-degree vertices; dense: half of the graph
+	var sparse []int
+	for v := 0; v < n && len(sparse) < 16; v++ {
+		if d := len(G[v]); d > 0 && d < 8 {
+			sparse = append(sparse, v)
+		}
+	}
+	dense := make([]bool, n)
+	for v := 0; v < n; v += 2 {
+		dense[v] = true
+	}
+	for _, tc := range []struct {
+		name     string
+		frontier func() VertexSubset
+	}{
+		{"sparse", func() VertexSubset { return NewSparse(sparse) }},
+		{"dense", func() VertexSubset { return NewDense(dense) }},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				run++
+				em.Run(tc.frontier(), false)
+			}
+		})
+	}
+}
+
+func BenchmarkCountTrue(b *testing.B) {
+	flags := make([]bool, 1<<20)
+	for i := range flags {
+		flags[i] = i%3 == 0
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		countTrue(flags)
+	}
+}
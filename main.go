@@ -1,18 +1,32 @@
 package main
 
 import (
-	"cluster_bfs_go/graphutils"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"runtime"
+	"strings"
+	"syscall"
 	"time"
 )
 
-func singleBatchTest(seeds [][]int, G, GT [][]int, t int, verify bool, R int, seq bool) { // par == True -> ClusterBFS; par == False -> Sequential BFS
+// singleBatchTest times t iterations over all seed batches and returns the timings
+// (Graph and M are left for the caller to fill in); it stops with a *PartialResultError once ctx is done
+func singleBatchTest(ctx context.Context, seeds [][]int, G, GT [][]int, t int, verify bool, R int, seq bool) (*BenchResult, error) { // seq == false -> ClusterBFS; seq == true -> Sequential BFS
 	ns := len(seeds)
 	k := len(seeds[0])
-	// n := len(G)
+	res := &BenchResult{
+		Time: time.Now(), Algorithm: "cbfs",
+		N: len(G), R: R, K: k, NS: ns, GOMAXPROCS: runtime.GOMAXPROCS(0),
+		BatchMs: make([]float64, ns),
+	}
+	if seq {
+		res.Algorithm = "seq"
+	}
 
 	fmt.Printf("Radius: %d\n", R)
 	fmt.Printf("Number of batches: %d, batch size k = %d\n", ns, k)
@@ -21,82 +35,178 @@ func singleBatchTest(seeds [][]int, G, GT [][]int, t int, verify bool, R int, se
 	firstBatch := seeds[0]
 	// Sequential BFS
 	if seq {
-		SequentialBFS(G, firstBatch)
+		if _, _, err := SequentialBFSContext(ctx, G, firstBatch); err != nil {
+			return nil, err
+		}
 	} else { // ClusterBFS
 		cbfs := &ClusterBFS{G: G, GT: GT, R: R} // allocate ClusterBFS once
 		goSeeds := cbfs.Init(firstBatch)
-		cbfs.RunCBFS(goSeeds)
+		if err := cbfs.RunCBFSContext(ctx, goSeeds); err != nil {
+			return nil, err
+		}
 		if verify {
-			if err := cbfs.VerifyCBFS(firstBatch); err != nil {
-				fmt.Fprintf(os.Stderr, "verification failed: %v\n", err)
-				os.Exit(1)
+			if err := cbfs.VerifyCBFSContext(ctx, firstBatch); err != nil {
+				return nil, fmt.Errorf("verification failed: %w", err)
 			}
 			fmt.Println("PASS correctness check!")
 		}
 	}
 
-	// timed runs
-	start := time.Now()
-	if seq {
-		for i := 0; i < t; i++ {
-			for _, batch := range seeds {
-				SequentialBFS(G, batch)
-			}
-			fmt.Printf("%d iteration done\n", i+1)
-		}
-	} else {
-		cbfs := &ClusterBFS{G: G, GT: GT, R: R} // allocate ClusterBFS
-		for i := 0; i < t; i++ {
-			for _, batch := range seeds {
+	// timed runs: every batch is timed on its own, and the heap is sampled between batches (outside the timings)
+	var heap heapSampler
+	heap.sample()
+	cbfs := &ClusterBFS{G: G, GT: GT, R: R} // allocate ClusterBFS
+	var elapsed time.Duration
+	for i := 0; i < t; i++ {
+		var iter time.Duration
+		for b, batch := range seeds {
+			start := time.Now()
+			var err error
+			if seq {
+				_, _, err = SequentialBFSContext(ctx, G, batch)
+			} else {
 				goSeeds := cbfs.Init(batch)
-				cbfs.RunCBFS(goSeeds)
+				err = cbfs.RunCBFSContext(ctx, goSeeds)
+			}
+			d := time.Since(start)
+			if err != nil {
+				return nil, fmt.Errorf("iteration %d, batch %d: %w", i+1, b, err)
 			}
-			fmt.Printf("%d iteration done\n", i+1)
+			iter += d
+			res.BatchMs[b] += durationMs(d) / float64(t)
+			heap.sample()
 		}
+		elapsed += iter
+		res.IterationMs = append(res.IterationMs, durationMs(iter))
+		fmt.Printf("%d iteration done\n", i+1)
 	}
-	elapsed := time.Since(start)
+	res.PeakHeapBytes = heap.peak
+	res.summarize()
 	avg := elapsed / time.Duration(t)
 	fmt.Printf("average cluster BFS time: %v\n", avg)
+	return res, nil
 }
 
-// Read the bin files and print part of the graph
-func main() {
-	// flags
-	var (
-		path   = flag.String("f", "", "path to graph.bin")
-		t      = flag.Int("t", 3, "number of iterations")
-		ns     = flag.Int("ns", 10, "number of seed batches")
-		k      = flag.Int("k", 64, "seeds per batch")
-		r      = flag.Int("r", 2, "BFS radius for verify")
-		verify = flag.Bool("v", false, "verify with Ligra BFS")
-		seq    = flag.Bool("seq", false, "if true, run ClusterBFS; if false, run Sequential BFS")
-		c      = flag.Int("c", 20, "number of CPU cores to use (GOMAXPROCS)")
-	)
-	flag.Parse()
-	if *path == "" {
-		fmt.Fprintln(os.Stderr, "Usage: -f graph.bin [-t #] [-ns #] [-k #] [-r #] [-v] [-par=true|false]")
-		os.Exit(1)
+// Exit codes
+const (
+	exitOK      = 0 // success (also for -h / help)
+	exitFailure = 1 // the command ran and failed (bad input file, verification mismatch, ...)
+	exitUsage   = 2 // bad command line
+)
+
+const programName = "cluster_bfs_go"
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
 	}
-	runtime.GOMAXPROCS(*c)
+	return nil
+}
 
-	// Read CSR and construct the graph
-	offs64, edges32, err := graphutils.ReadGraphFromBin(*path)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading graph: %v\n", err)
-		os.Exit(1)
+// printUsage lists the commands
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", programName)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintf(w, "\nRun '%s help <command>' (or '%s <command> -h') for the flags of a command.\n", programName, programName)
+	fmt.Fprintf(w, "Without a command, the flags are passed to bench (e.g. '%s -f graph.bin -t 3').\n", programName)
+}
 
-	// Build Go adjacent lists
-	G := graphutils.BuildAdjFromCSR(offs64, edges32)
-	GT := graphutils.TransposeAdj(G)
+// newFlagSet creates the flag set of cmd with its per-command help text
+// The returned action runs the command under the profiling and -timeout flags shared by all commands;
+// SIGINT / SIGTERM cancel it like the timeout does
+func newFlagSet(cmd *command, out io.Writer) (*flag.FlagSet, func() error) {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	run := cmd.setup(fs)
+	var prof profileOptions
+	prof.register(fs)
+	timeout := fs.Duration("timeout", 0, "stop the command after this long, e.g. 90s or 10m (0: no limit)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags]\n\n%s\n\nFlags:\n", programName, cmd.name, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs, func() error {
+		stop, err := prof.start(os.Stderr)
+		if err != nil {
+			return fmt.Errorf("starting profiles: %w", err)
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		if *timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		err = run(ctx)
+		if perr := stop(); perr != nil && err == nil {
+			err = fmt.Errorf("writing profiles: %w", perr)
+		}
+		return err
+	}
+}
 
-	// Select seeds
-	seeds := make([][]int, *ns)
-	for i := range seeds {
-		seeds[i] = make([]int, *k)
+// runCLI dispatches args (without the program name) to a command and returns the exit code
+func runCLI(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "-h", "-help", "--help":
+		printUsage(os.Stdout)
+		return exitOK
+	case "help":
+		if len(args) == 1 {
+			printUsage(os.Stdout)
+			return exitOK
+		}
+		cmd := findCommand(args[1])
+		if cmd == nil {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[1])
+			printUsage(os.Stderr)
+			return exitUsage
+		}
+		fs, _ := newFlagSet(cmd, os.Stdout)
+		fs.Usage()
+		return exitOK
+	}
+	// flags without a command: the original single-command interface, i.e. bench
+	if strings.HasPrefix(args[0], "-") {
+		args = append([]string{"bench"}, args...)
 	}
-	graphutils.SelectSeeds1(G, seeds)
 
-	// run single‐batch test
-	singleBatchTest(seeds, G, GT, *t, *verify, *r, *seq)
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		return exitUsage
+	}
+	fs, run := newFlagSet(cmd, os.Stderr)
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage // the flag package already printed the error and the usage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "%s: unexpected argument %q\n", cmd.name, fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+		if isUsageError(err) {
+			fs.Usage()
+			return exitUsage
+		}
+		return exitFailure
+	}
+	return exitOK
+}
+
+func main() {
+	os.Exit(runCLI(os.Args[1:]))
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"cluster_bfs_go/cbfspb"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Answer kinds of a distance query, the values of the "answer" label of cbfs_query_answers_total
+const (
+	answerExact       = "exact"       // index estimate certified exact by the labels (Oracle.QueryExact)
+	answerBiBFS       = "bibfs"       // not certified, found by the fallback BiBFS
+	answerBound       = "bound"       // upper bound of the index only
+	answerUnreachable = "unreachable" // not covered by the index nor found by the BiBFS
+)
+
+// Histogram buckets (upper bounds in seconds)
+var (
+	latencyBuckets = []float64{.00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	buildBuckets   = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25, 50, 100}
+)
+
+// histogram is a Prometheus histogram: counts[i] observations in (bounds[i-1], bounds[i]], the last one above every bound
+type histogram struct {
+	mu     sync.Mutex
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(x float64) {
+	i := sort.SearchFloat64s(h.bounds, x)
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += x
+	h.count++
+	h.mu.Unlock()
+}
+
+// write prints the _bucket, _sum and _count series; labels is empty or like `endpoint="distance"`
+func (h *histogram) write(w io.Writer, name, labels string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	bucketLabels, seriesLabels := "", ""
+	if labels != "" {
+		bucketLabels, seriesLabels = labels+",", "{"+labels+"}"
+	}
+	cumulative := uint64(0)
+	for i, c := range h.counts {
+		cumulative += c
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = strconv.FormatFloat(h.bounds[i], 'g', -1, 64)
+		}
+		fmt.Fprintf(w, "%s_bucket{%sle=\"%s\"} %d\n", name, bucketLabels, le, cumulative)
+	}
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, seriesLabels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, seriesLabels, h.count)
+}
+
+// serverMetrics are the metrics of the serve command, exposed on /metrics in the Prometheus text format
+// Every label value is known up front, so the maps are read-only after newServerMetrics
+type serverMetrics struct {
+	latency    map[string]*histogram     // by endpoint
+	answers    map[string]*atomic.Uint64 // by answer kind
+	build      *histogram                // per-batch build times of the loaded index
+	dBytes     uint64
+	sBytes     uint64
+	endpoints  []string
+	answerKeys []string
+}
+
+// HTTP and gRPC endpoints whose latency is recorded
+var metricEndpoints = []string{
+	"distance", "distance_batch", "neighbors_within",
+	cbfspb.ClusterBFS_Distance_FullMethodName, cbfspb.ClusterBFS_BatchDistance_FullMethodName, cbfspb.ClusterBFS_RunJob_FullMethodName,
+}
+
+func newServerMetrics(o *Oracle) *serverMetrics {
+	m := &serverMetrics{
+		latency:    map[string]*histogram{},
+		answers:    map[string]*atomic.Uint64{},
+		build:      newHistogram(buildBuckets),
+		endpoints:  metricEndpoints,
+		answerKeys: []string{answerExact, answerBiBFS, answerBound, answerUnreachable},
+	}
+	for _, e := range m.endpoints {
+		m.latency[e] = newHistogram(latencyBuckets)
+	}
+	for _, a := range m.answerKeys {
+		m.answers[a] = new(atomic.Uint64)
+	}
+	for _, t := range o.BatchTimes {
+		m.build.observe(t.Seconds())
+	}
+	m.dBytes, m.sBytes = o.LabelBytes()
+	return m
+}
+
+func (m *serverMetrics) observeLatency(endpoint string, d time.Duration) {
+	if h, ok := m.latency[endpoint]; ok {
+		h.observe(d.Seconds())
+	}
+}
+
+func (m *serverMetrics) countAnswer(kind string) {
+	m.answers[kind].Add(1)
+}
+
+// writeTo prints every metric in the Prometheus text exposition format (version 0.0.4)
+func (m *serverMetrics) writeTo(w io.Writer) {
+	fmt.Fprintln(w, "# HELP cbfs_query_duration_seconds Latency of query requests by endpoint.")
+	fmt.Fprintln(w, "# TYPE cbfs_query_duration_seconds histogram")
+	for _, e := range m.endpoints {
+		m.latency[e].write(w, "cbfs_query_duration_seconds", fmt.Sprintf("endpoint=%q", e))
+	}
+
+	fmt.Fprintln(w, "# HELP cbfs_query_answers_total Distance queries by answer: exact (certified by the labels), bibfs (fallback BiBFS), bound (index upper bound), unreachable.")
+	fmt.Fprintln(w, "# TYPE cbfs_query_answers_total counter")
+	for _, a := range m.answerKeys {
+		fmt.Fprintf(w, "cbfs_query_answers_total{answer=%q} %d\n", a, m.answers[a].Load())
+	}
+
+	fmt.Fprintln(w, "# HELP cbfs_index_build_batch_duration_seconds ClusterBFS time per seed batch when the loaded index was built.")
+	fmt.Fprintln(w, "# TYPE cbfs_index_build_batch_duration_seconds histogram")
+	m.build.write(w, "cbfs_index_build_batch_duration_seconds", "")
+
+	fmt.Fprintln(w, "# HELP cbfs_index_label_bytes Memory held by the D and S label arrays of the loaded index.")
+	fmt.Fprintln(w, "# TYPE cbfs_index_label_bytes gauge")
+	fmt.Fprintf(w, "cbfs_index_label_bytes{array=\"D\"} %d\n", m.dBytes)
+	fmt.Fprintf(w, "cbfs_index_label_bytes{array=\"S\"} %d\n", m.sBytes)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"cluster_bfs_go/graphutils"
+	"cluster_bfs_go/parlay_go"
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"time"
+)
+
+// Oracle is a distance oracle built from ClusterBFS labels: one (D, S) pair per seed batch
+// (the Go analogue of LandmarkLabeling_batch in ADO_cluster.h)
+type Oracle struct {
+	R     int          // Radius the labels were built with
+	Seeds [][]int      // Seed batches, as passed to ClusterBFS
+	D     [][]uint64   // D[i][v]: round in which batch i first reached v
+	S     [][][]uint64 // S[i][v][r]: seeds of batch i that first reached v in round D[i][v]+r
+	INF   uint64
+	// ToLocal[v]: ID in the labels of vertex v of the input graph (-1: dropped, e.g. by -lcc);
+	// nil when the labels use the input IDs
+	ToLocal []int
+	// BatchTimes[i]: ClusterBFS time of batch i when the oracle was built (nil if unknown)
+	BatchTimes []time.Duration
+}
+
+// errNotSymmetric rejects directed graphs where an oracle's distances are served or saved:
+// the bounds of QueryExact (and Query's upper bound) need d(u, v) = d(v, u)
+var errNotSymmetric = errors.New("the distance oracle needs a symmetric graph")
+
+// BuildOracle runs ClusterBFS once per seed batch and keeps every batch's labels
+func BuildOracle(G, GT [][]int, seeds [][]int, R int) *Oracle {
+	o, _ := BuildOracleContext(context.Background(), G, GT, seeds, R) // never cancelled
+	return o
+}
+
+// BuildOracleContext is BuildOracle with cancellation: when ctx is done it returns the oracle of the
+// batches labeled so far (a batch cut short is dropped) with a *PartialResultError counting them
+func BuildOracleContext(ctx context.Context, G, GT [][]int, seeds [][]int, R int) (*Oracle, error) {
+	o := &Oracle{R: R, INF: ^uint64(0)}
+	cbfs := &ClusterBFS{G: G, GT: GT, R: R}
+	for i, batch := range seeds {
+		start := time.Now()
+		goSeeds := cbfs.Init(batch)
+		if err := cbfs.RunCBFSContext(ctx, goSeeds); err != nil {
+			var partial *PartialResultError
+			if errors.As(err, &partial) {
+				err = partial.Err
+			}
+			return o, &PartialResultError{Op: "BuildOracle", Unit: "batches", Completed: i, Err: err}
+		}
+		o.BatchTimes = append(o.BatchTimes, time.Since(start))
+		// Init allocates fresh slices, so the outputs can be kept without copying
+		o.Seeds = append(o.Seeds, batch)
+		o.D = append(o.D, cbfs.D)
+		o.S = append(o.S, cbfs.S)
+	}
+	return o, nil
+}
+
+// queryHelper bounds d(u, v) through batch i, like query_helper in ADO_cluster.h:
+// a seed j that reaches u in round D[u]+a and v in round D[v]+b gives d(u, v) ≤ D[u]+a + D[v]+b,
+// so the smallest a+b with S[u][a] & S[v][b] ≠ 0 gives the best bound. Returns INF if no seed reaches both.
+func (o *Oracle) queryHelper(u, v, i int) uint64 {
+	D, S := o.D[i], o.S[i]
+	if D[u] == o.INF || D[v] == o.INF {
+		return o.INF
+	}
+	for sum := 0; sum <= 2*(o.R-1); sum++ {
+		for a := max(0, sum-(o.R-1)); a <= min(sum, o.R-1); a++ {
+			if S[u][a]&S[v][sum-a] != 0 {
+				return D[u] + D[v] + uint64(sum)
+			}
+		}
+	}
+	return o.INF
+}
+
+// Local maps a vertex of the input graph to its ID in the labels (false if it is not in the labeled graph)
+func (o *Oracle) Local(v int) (int, bool) {
+	if o.ToLocal == nil {
+		return v, v >= 0 && len(o.D) > 0 && v < len(o.D[0])
+	}
+	if v < 0 || v >= len(o.ToLocal) || o.ToLocal[v] < 0 {
+		return 0, false
+	}
+	return o.ToLocal[v], true
+}
+
+// Query returns the best upper bound on d(u, v) over all batches (INF if no batch covers both)
+func (o *Oracle) Query(u, v int) uint64 {
+	if u == v {
+		return 0
+	}
+	best := o.INF
+	for i := range o.D {
+		if d := o.queryHelper(u, v, i); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// QueryExact is Query plus a certificate of exactness. A seed s that reaches u in round du and v in round dv
+// (the labels record first arrivals, so du = d(s, u) and dv = d(s, v)) gives |du-dv| ≤ d(u, v) ≤ du+dv;
+// the estimate is exact when it equals the best of these lower bounds. Like Query, it assumes a symmetric graph
+func (o *Oracle) QueryExact(u, v int) (d uint64, exact bool) {
+	if u == v {
+		return 0, true
+	}
+	best, lower := o.INF, uint64(0)
+	for i := range o.D {
+		D, S := o.D[i], o.S[i]
+		if D[u] == o.INF || D[v] == o.INF {
+			continue
+		}
+		for a := 0; a < o.R; a++ {
+			if S[u][a] == 0 {
+				continue
+			}
+			for b := 0; b < o.R; b++ {
+				if S[u][a]&S[v][b] == 0 {
+					continue
+				}
+				du, dv := D[u]+uint64(a), D[v]+uint64(b)
+				best = min(best, du+dv)
+				lower = max(lower, max(du, dv)-min(du, dv))
+			}
+		}
+	}
+	return best, best != o.INF && best == lower
+}
+
+// queryGroupBits sets the groups of QueryBatch: 2^queryGroupBits consecutive vertices, whose labels fit in cache
+const queryGroupBits = 10
+
+// QueryBatch answers QueryExact for every pair in parallel: d[i] and exact[i] are the answer for pairs[i].
+// The pairs are grouped by their smaller endpoint (QueryExact is symmetric in u and v), so the queries of
+// one vertex run back to back on one worker and the D / S entries of the smaller endpoints are read in
+// vertex order, even when no vertex repeats. Like Query, it takes IDs of the labeled graph;
+// a pair with an ID outside them gets INF, not exact
+func (o *Oracle) QueryBatch(pairs [][2]int) (d []uint64, exact []bool) {
+	d, exact = make([]uint64, len(pairs)), make([]bool, len(pairs))
+	n := 0
+	if len(o.D) > 0 {
+		n = len(o.D[0])
+	}
+	// Bad IDs are answered while counting, before anything is indexed by them:
+	// a panic in a ParallelFor goroutine cannot be recovered
+	blocks := min(runtime.GOMAXPROCS(0), len(pairs))
+	block := func(b int) (lo, hi int) { return b * len(pairs) / blocks, (b + 1) * len(pairs) / blocks }
+	groups := n>>queryGroupBits + 1
+	counts := make([]int, groups*blocks) // counts[g*blocks+b]: pairs of block b in group g
+	parlay_go.ParallelFor(blocks, func(b int) {
+		lo, hi := block(b)
+		for i := lo; i < hi; i++ {
+			u, v := pairs[i][0], pairs[i][1]
+			if u < 0 || u >= n || v < 0 || v >= n {
+				d[i] = o.INF
+				continue
+			}
+			counts[min(u, v)>>queryGroupBits*blocks+b]++
+		}
+	})
+
+	// 1) Parallel counting sort of the pairs by group: the scan of the group-major counts gives every block
+	// its own run of slots in every group, which it fills in input order
+	type keyed struct{ u, v, i int }
+	next, total := parlay_go.Scan(counts)
+	sorted := make([]keyed, total)
+	parlay_go.ParallelFor(blocks, func(b int) {
+		lo, hi := block(b)
+		for i := lo; i < hi; i++ {
+			u, v := pairs[i][0], pairs[i][1]
+			if d[i] == o.INF {
+				continue
+			}
+			slot := &next[min(u, v)>>queryGroupBits*blocks+b]
+			sorted[*slot] = keyed{min(u, v), max(u, v), i}
+			*slot++
+		}
+	})
+
+	// 2) Answer them in that order; ParallelFor hands out contiguous blocks, so a group stays on one worker
+	parlay_go.ParallelFor(len(sorted), func(j int) {
+		p := sorted[j]
+		d[p.i], exact[p.i] = o.QueryExact(p.u, p.v)
+	})
+	return d, exact
+}
+
+// LabelBytes is the memory held by the labels: d for the D arrays, s for the S arrays (including the slice headers)
+func (o *Oracle) LabelBytes() (d, s uint64) {
+	for i := range o.D {
+		d += uint64(len(o.D[i])) * 8
+		s += uint64(len(o.S[i])) * (24 + uint64(o.R)*8)
+	}
+	return d, s
+}
+
+// OracleReport summarizes how well an oracle answers a set of ground-truth pairs
+type OracleReport struct {
+	Pairs       int     // pairs evaluated
+	Covered     int     // pairs with a finite estimate
+	Exact       int     // pairs whose estimate equals the true distance
+	MeanStretch float64 // mean estimate / true distance over covered pairs with d > 0
+	MaxStretch  float64
+}
+
+// EvaluateOracle compares the oracle's answers with ground-truth distances
+func EvaluateOracle(o *Oracle, truth []graphutils.DistancePair) OracleReport {
+	rep := OracleReport{Pairs: len(truth)}
+	sum := 0.0
+	stretched := 0
+	pairs := make([][2]int, len(truth))
+	for i, p := range truth {
+		pairs[i] = [2]int{p.U, p.V}
+	}
+	ests, _ := o.QueryBatch(pairs)
+	for i, p := range truth {
+		est := ests[i]
+		if est == o.INF {
+			continue
+		}
+		rep.Covered++
+		if est == uint64(p.D) {
+			rep.Exact++
+		}
+		if p.D > 0 {
+			s := float64(est) / float64(p.D)
+			sum += s
+			stretched++
+			rep.MaxStretch = math.Max(rep.MaxStretch, s)
+		}
+	}
+	if stretched > 0 {
+		rep.MeanStretch = sum / float64(stretched)
+	}
+	return rep
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"time"
+)
+
+// Oracle index files store the labels of BuildOracle so they can be queried without rerunning ClusterBFS
+/*
+Data format (little endian):
+magic "CBFSIDX2" (8 bytes)
+n, R, ns, k, nOrig, nTimes (6×uint64)  nOrig = len(ToLocal), 0 when the labels use the input IDs;
+                                       nTimes = len(BatchTimes), ns or 0
+Seeds (ns×k×int64)
+ToLocal (nOrig×int64)
+for each batch: D (n×uint64), S (n×R×uint64, S[v][0…R-1] for v = 0…n-1)
+BatchTimes (nTimes×int64 nanoseconds)
+
+Version 1 files ("CBFSIDX1") have no nTimes and no BatchTimes, and are still read
+*/
+const (
+	oracleMagic   = "CBFSIDX2"
+	oracleMagicV1 = "CBFSIDX1"
+)
+
+// SaveOracle writes the oracle to path in the index format above
+func SaveOracle(path string, o *Oracle) (err error) {
+	if len(o.D) == 0 || len(o.D) != len(o.Seeds) {
+		return fmt.Errorf("oracle has %d label sets for %d seed batches", len(o.D), len(o.Seeds))
+	}
+	n, k := len(o.D[0]), len(o.Seeds[0])
+	for _, batch := range o.Seeds {
+		if len(batch) != k {
+			return fmt.Errorf("seed batches of different sizes (%d and %d)", k, len(batch))
+		}
+	}
+	if len(o.BatchTimes) != 0 && len(o.BatchTimes) != len(o.Seeds) {
+		return fmt.Errorf("oracle has %d batch times for %d seed batches", len(o.BatchTimes), len(o.Seeds))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	write := func(x interface{}) {
+		if err == nil {
+			err = binary.Write(w, binary.LittleEndian, x)
+		}
+	}
+	toInt64 := func(vs []int) []int64 {
+		out := make([]int64, len(vs))
+		for i, v := range vs {
+			out[i] = int64(v)
+		}
+		return out
+	}
+
+	write([]byte(oracleMagic))
+	write([]uint64{uint64(n), uint64(o.R), uint64(len(o.Seeds)), uint64(k), uint64(len(o.ToLocal)), uint64(len(o.BatchTimes))})
+	for _, batch := range o.Seeds {
+		write(toInt64(batch))
+	}
+	write(toInt64(o.ToLocal))
+	row := make([]uint64, 0, n*o.R)
+	for i := range o.D {
+		write(o.D[i])
+		row = row[:0]
+		for _, s := range o.S[i] {
+			row = append(row, s...)
+		}
+		write(row)
+	}
+	times := make([]int64, len(o.BatchTimes))
+	for i, t := range o.BatchTimes {
+		times[i] = int64(t)
+	}
+	write(times)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return w.Flush()
+}
+
+// LoadOracle reads an index written by SaveOracle
+func LoadOracle(path string) (*Oracle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", path, err)
+	}
+	r := bufio.NewReader(f)
+
+	fail := func(err error) (*Oracle, error) {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	magic := make([]byte, len(oracleMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return fail(err)
+	}
+	hdr := make([]uint64, 6)
+	switch string(magic) {
+	case oracleMagic:
+	case oracleMagicV1:
+		hdr = hdr[:5]
+	default:
+		return nil, fmt.Errorf("%s: not an oracle index", path)
+	}
+	if err := binary.Read(r, binary.LittleEndian, hdr); err != nil {
+		return fail(err)
+	}
+	// Every count must fit in the rest of the file before it is used as a length,
+	// so a corrupt header is an error rather than a huge (or overflowing) allocation
+	rest := uint64(max(st.Size()-int64(len(magic)+8*len(hdr)), 0))
+	words, ok := oracleWords(hdr)
+	if !ok || words > rest/8 {
+		return nil, fmt.Errorf("%s: header %v does not match the file size (%d bytes)", path, hdr, st.Size())
+	}
+	n, R, ns, k, nOrig := int(hdr[0]), int(hdr[1]), int(hdr[2]), int(hdr[3]), int(hdr[4])
+	nTimes := 0
+	if len(hdr) == 6 {
+		nTimes = int(hdr[5])
+	}
+	if R < 1 || k < 1 || k > 64 || ns < 1 || (nTimes != 0 && nTimes != ns) {
+		return nil, fmt.Errorf("%s: bad header n=%d R=%d ns=%d k=%d", path, n, R, ns, k)
+	}
+
+	readInts := func(count int) ([]int, error) {
+		buf := make([]int64, count)
+		if err := binary.Read(r, binary.LittleEndian, buf); err != nil {
+			return nil, err
+		}
+		out := make([]int, count)
+		for i, v := range buf {
+			out[i] = int(v)
+		}
+		return out, nil
+	}
+
+	o := &Oracle{R: R, INF: ^uint64(0)}
+	for i := 0; i < ns; i++ {
+		batch, err := readInts(k)
+		if err != nil {
+			return fail(err)
+		}
+		o.Seeds = append(o.Seeds, batch)
+	}
+	if nOrig > 0 {
+		if o.ToLocal, err = readInts(nOrig); err != nil {
+			return fail(err)
+		}
+		for v, l := range o.ToLocal {
+			if l < -1 || l >= n {
+				return nil, fmt.Errorf("%s: vertex %d mapped to %d, outside -1…%d", path, v, l, n-1)
+			}
+		}
+	}
+	for i := 0; i < ns; i++ {
+		D := make([]uint64, n)
+		if err := binary.Read(r, binary.LittleEndian, D); err != nil {
+			return fail(err)
+		}
+		flat := make([]uint64, n*R)
+		if err := binary.Read(r, binary.LittleEndian, flat); err != nil {
+			return fail(err)
+		}
+		S := make([][]uint64, n)
+		for v := range S {
+			S[v] = flat[v*R : (v+1)*R : (v+1)*R]
+		}
+		o.D = append(o.D, D)
+		o.S = append(o.S, S)
+	}
+	if nTimes > 0 {
+		times := make([]int64, nTimes)
+		if err := binary.Read(r, binary.LittleEndian, times); err != nil {
+			return fail(err)
+		}
+		for _, t := range times {
+			o.BatchTimes = append(o.BatchTimes, time.Duration(t))
+		}
+	}
+	return o, nil
+}
+
+// oracleWords is the number of 8-byte words after the header hdr (n, R, ns, k, nOrig[, nTimes]);
+// false if it overflows
+func oracleWords(hdr []uint64) (uint64, bool) {
+	n, R, ns, k, nOrig := hdr[0], hdr[1], hdr[2], hdr[3], hdr[4]
+	words, ok := uint64(0), true
+	add := func(a, b uint64) {
+		hi, lo := bits.Mul64(a, b)
+		sum, carry := bits.Add64(words, lo, 0)
+		ok = ok && hi == 0 && carry == 0
+		words = sum
+	}
+	overflow, perBatch := bits.Mul64(n, R)
+	perBatch, carry := bits.Add64(perBatch, n, 0) // D and S of one batch
+	ok = overflow == 0 && carry == 0
+	add(ns, k)
+	add(nOrig, 1)
+	add(ns, perBatch)
+	if len(hdr) == 6 {
+		add(hdr[5], 1)
+	}
+	return words, ok
+}
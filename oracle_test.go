@@ -0,0 +1,168 @@
+package main
+
+import (
+	"cluster_bfs_go/graphutils"
+	"cluster_bfs_go/parlay_go"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Estimates are upper bounds, and exact for pairs on opposite sides of the batch
+func TestOracleOnPath(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.PathGraph(12))
+	oracle := BuildOracle(G, G, [][]int{{5, 4, 6}}, 3)
+	cases := []graphutils.DistancePair{{U: 0, V: 11, D: 11}, {U: 5, V: 9, D: 4}, {U: 0, V: 3, D: 3}, {U: 7, V: 7, D: 0}}
+	for _, p := range cases {
+		got := oracle.Query(p.U, p.V)
+		if got < uint64(p.D) {
+			t.Fatalf("d(%d,%d): estimate %d below the true distance %d", p.U, p.V, got, p.D)
+		}
+	}
+	if got := oracle.Query(0, 11); got != 11 {
+		t.Fatalf("d(0,11) = %d, want 11", got)
+	}
+	// a seed at one end certifies the estimate, a path through the seeds cannot be told from a shortcut
+	if d, exact := oracle.QueryExact(5, 9); d != 4 || !exact {
+		t.Fatalf("QueryExact(5, 9) = %d, %v; want 4, exact", d, exact)
+	}
+	if d, exact := oracle.QueryExact(0, 11); d != 11 || exact {
+		t.Fatalf("QueryExact(0, 11) = %d, %v; want 11, not certified", d, exact)
+	}
+	rep := EvaluateOracle(oracle, cases)
+	if rep.Covered != len(cases) || rep.MeanStretch < 1 {
+		t.Fatalf("unexpected report %+v", rep)
+	}
+}
+
+func TestOracleSaveLoad(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.Grid2D(5, 6))
+	oracle := BuildOracle(G, G, [][]int{{0, 1, 6}, {29, 28, 29}}, 3)
+	oracle.ToLocal = make([]int, len(G)+2)
+	for v := range oracle.ToLocal {
+		oracle.ToLocal[v] = max(v-2, -1) // two dropped input vertices in front
+	}
+	path := filepath.Join(t.TempDir(), "grid.idx")
+	if err := SaveOracle(path, oracle); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadOracle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(oracle, loaded) || len(loaded.BatchTimes) != 2 {
+		t.Fatal("oracle changed after a save/load round trip")
+	}
+	if _, ok := loaded.Local(1); ok {
+		t.Fatal("dropped vertex should not be mapped")
+	}
+	if v, ok := loaded.Local(7); !ok || v != 5 {
+		t.Fatalf("Local(7) = %d, %v", v, ok)
+	}
+
+	// version 1 files are the version 2 layout without nTimes and the batch times
+	oracle.BatchTimes = nil
+	if err := SaveOracle(path, oracle); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1 := append([]byte(oracleMagicV1), data[8:48]...)
+	v1 = append(v1, data[56:]...)
+	if err := os.WriteFile(path, v1, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err = LoadOracle(path); err != nil || !reflect.DeepEqual(oracle, loaded) {
+		t.Fatalf("version 1 index not read back (%v)", err)
+	}
+	if _, err := LoadOracle(filepath.Join(t.TempDir(), "missing.idx")); err == nil {
+		t.Fatal("expected an error for a missing index")
+	}
+
+	// corrupt headers and out-of-range mappings are errors, not panics
+	corrupt := func(at int, word uint64) []byte {
+		bad := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint64(bad[at:], word)
+		return bad
+	}
+	for name, bad := range map[string][]byte{
+		"huge n":       corrupt(8, ^uint64(0)),
+		"n*R overflow": corrupt(16, 1<<62),
+		"huge nOrig":   corrupt(40, 1<<40),
+		"bad ToLocal":  corrupt(56+8*2*3+8*(len(G)+1), uint64(len(G))),
+	} {
+		if err := os.WriteFile(path, bad, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadOracle(path); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+// QueryBatch answers every pair like QueryExact, whatever the order and repetitions of the pairs
+func TestQueryBatch(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.ErdosRenyi(300, 900, 7))
+	oracle := BuildOracle(G, G, [][]int{benchBatch(G, 16), {0, 1, 2}}, 3)
+	rng := rand.New(rand.NewPCG(1, 2))
+	pairs := make([][2]int, 5000)
+	for i := range pairs {
+		pairs[i] = [2]int{rng.IntN(len(G)), rng.IntN(len(G))}
+	}
+	pairs = append(pairs, [2]int{7, 7}, [2]int{3, 9}, [2]int{9, 3})
+	d, exact := oracle.QueryBatch(pairs)
+	for i, p := range pairs {
+		wantD, wantExact := oracle.QueryExact(p[0], p[1])
+		if d[i] != wantD || exact[i] != wantExact {
+			t.Fatalf("pair %d %v: got %d, %v; QueryExact gives %d, %v", i, p, d[i], exact[i], wantD, wantExact)
+		}
+	}
+	if d, exact := oracle.QueryBatch(nil); len(d) != 0 || len(exact) != 0 {
+		t.Fatal("expected no answers for no pairs")
+	}
+	// IDs outside the labels are answered INF instead of panicking in a worker
+	d, exact = oracle.QueryBatch([][2]int{{0, 5}, {-1, 3}, {3, len(G)}, {len(G), len(G)}})
+	if d[0] == oracle.INF || d[1] != oracle.INF || d[2] != oracle.INF || d[3] != oracle.INF || exact[1] || exact[2] || exact[3] {
+		t.Fatalf("unexpected answers %v, %v for out-of-range pairs", d, exact)
+	}
+}
+
+// BenchmarkQueryBatch compares QueryBatch, which groups the pairs by vertex, with the same queries
+// answered in input order, for batches smaller and larger than the graph
+func BenchmarkQueryBatch(b *testing.B) {
+	G := benchGraph()
+	oracle := BuildOracle(G, G, [][]int{benchBatch(G, 64), benchBatch(G, 8)}, 2)
+	rng := rand.New(rand.NewPCG(3, 4))
+	for _, size := range []int{len(G) / 4, len(G), 4 * len(G)} {
+		pairs := make([][2]int, size)
+		for i := range pairs {
+			pairs[i] = [2]int{rng.IntN(len(G)), rng.IntN(len(G))}
+		}
+		b.Run(fmt.Sprintf("pairs=%d/QueryExact", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, p := range pairs {
+					oracle.QueryExact(p[0], p[1])
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("pairs=%d/InputOrder", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				parlay_go.ParallelFor(len(pairs), func(j int) { oracle.QueryExact(pairs[j][0], pairs[j][1]) })
+			}
+		})
+		b.Run(fmt.Sprintf("pairs=%d/QueryBatch", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				oracle.QueryBatch(pairs)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package parlay_go
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 1000} {
+		src := make([]int, n)
+		for i := range src {
+			src[i] = i * 7
+		}
+		dst := make([]int, n)
+		Append(src, dst)
+		if !slices.Equal(src, dst) {
+			t.Fatalf("n=%d: copy differs", n)
+		}
+	}
+}
+
+func TestPackIndex(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 1001} {
+		dense := make([]bool, n)
+		var want []int
+		for i := range dense {
+			if i%3 == 0 {
+				dense[i] = true
+				want = append(want, i)
+			}
+		}
+		if got := PackIndex(dense); !slices.Equal(got, want) {
+			t.Fatalf("n=%d: got %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestParallelFor(t *testing.T) {
+	for _, n := range []int{0, 1, 17, 10000} {
+		hits := make([]int, n)
+		ParallelFor(n, func(i int) { hits[i]++ })
+		for i, h := range hits {
+			if h != 1 {
+				t.Fatalf("n=%d: index %d visited %d times", n, i, h)
+			}
+		}
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	src := make([]int, 1<<20)
+	dst := make([]int, len(src))
+	b.ReportAllocs()
+	b.SetBytes(int64(len(src)) * 8)
+	for i := 0; i < b.N; i++ {
+		Append(src, dst)
+	}
+}
+
+func BenchmarkPackIndex(b *testing.B) {
+	for _, every := range []int{2, 64} {
+		b.Run(fmt.Sprintf("1in%d", every), func(b *testing.B) {
+			dense := make([]bool, 1<<20)
+			for i := 0; i < len(dense); i += every {
+				dense[i] = true
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				PackIndex(dense)
+			}
+		})
+	}
+}
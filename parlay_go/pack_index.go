@@ -8,6 +8,9 @@ import (
 // Helper function "parlay::pack_index" called by function "AddVertices" in ligra_light.go
 func PackIndex(dense []bool) []int {
 	n := len(dense)
+	if n == 0 { // To avoid "integer divide by zero" when calculating chunk later
+		return []int{}
+	}
 	workers := runtime.GOMAXPROCS(0)
 	if workers > n {
 		workers = n
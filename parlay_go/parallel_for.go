@@ -0,0 +1,35 @@
+package parlay_go
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Helper function "parlay::parallel_for": runs f(i) for every i in [0, n)
+// The range is split into one contiguous block per logical CPU, and each block is handled by its own goroutine
+func ParallelFor(n int, f func(i int)) {
+	if n == 0 { // To avoid "integer divide by zero" when calculating chunk later
+		return
+	}
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	chunk := (n + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += chunk {
+		hi := lo + chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				f(i)
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package parlay_go
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Helper function "parlay::scan": returns the exclusive prefix sums of in, plus the total
+// Two passes over one block per worker: block sums first, then each block writes its prefix sums from its start value
+func Scan[T ~int | ~int64 | ~uint64](in []T) ([]T, T) {
+	n := len(in)
+	out := make([]T, n)
+	if n == 0 {
+		return out, 0
+	}
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	chunk := (n + workers - 1) / workers
+	blocks := (n + chunk - 1) / chunk
+
+	// Pass 1: sum of every block
+	sums := make([]T, blocks)
+	var wg sync.WaitGroup
+	for b := 0; b < blocks; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			lo, hi := b*chunk, min((b+1)*chunk, n)
+			var s T
+			for i := lo; i < hi; i++ {
+				s += in[i]
+			}
+			sums[b] = s
+		}(b)
+	}
+	wg.Wait()
+
+	// Sequential scan over the (few) block sums
+	var total T
+	for b, s := range sums {
+		sums[b] = total
+		total += s
+	}
+
+	// Pass 2: each block fills its part of out starting from its offset
+	for b := 0; b < blocks; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			lo, hi := b*chunk, min((b+1)*chunk, n)
+			s := sums[b]
+			for i := lo; i < hi; i++ {
+				out[i] = s
+				s += in[i]
+			}
+		}(b)
+	}
+	wg.Wait()
+	return out, total
+}
@@ -0,0 +1,23 @@
+package parlay_go
+
+import "testing"
+
+func TestScan(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 1000} {
+		in := make([]int, n)
+		for i := range in {
+			in[i] = i%5 + 1
+		}
+		out, total := Scan(in)
+		sum := 0
+		for i := range in {
+			if out[i] != sum {
+				t.Fatalf("n=%d: out[%d]=%d, want %d", n, i, out[i], sum)
+			}
+			sum += in[i]
+		}
+		if total != sum {
+			t.Fatalf("n=%d: total=%d, want %d", n, total, sum)
+		}
+	}
+}
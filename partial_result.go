@@ -0,0 +1,34 @@
+package main
+
+import "fmt"
+
+// PartialResultError is returned by a traversal stopped by its context (cancelled or past its deadline)
+// Completed counts the units of work that finished before the stop, and their results are valid:
+//   - ClusterBFS, SequentialBFS: rounds; D and S are final for every distance below Completed
+//   - EdgeMap: vertices of the output frontier found so far (a subset of the full frontier)
+//   - VerifyCBFS: seeds checked
+//   - BuildOracle: seed batches labeled (the oracle returned with the error holds them)
+//
+// errors.Is(err, context.Canceled) and errors.Is(err, context.DeadlineExceeded) see through it
+type PartialResultError struct {
+	Op        string // ClusterBFS, EdgeMap, SequentialBFS, VerifyCBFS or BuildOracle
+	Unit      string // what Completed counts
+	Completed int
+	Err       error // the context's error
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("%s stopped after %d %s: %v", e.Op, e.Completed, e.Unit, e.Err)
+}
+
+func (e *PartialResultError) Unwrap() error { return e.Err }
+
+// canceled reports whether done is closed, without blocking (done is nil for a context that is never cancelled)
+func canceled(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
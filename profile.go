@@ -0,0 +1,208 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+)
+
+// profileOptions are the profiling flags every command accepts
+type profileOptions struct {
+	cpu, mem, trace, block string
+	quiet                  bool
+}
+
+func (o *profileOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.cpu, "cpuprofile", "", "write a CPU profile to this file")
+	fs.StringVar(&o.mem, "memprofile", "", "write a heap profile to this file at the end of the run")
+	fs.StringVar(&o.trace, "trace", "", "write an execution trace to this file")
+	fs.StringVar(&o.block, "blockprofile", "", "write a goroutine blocking profile to this file")
+	fs.BoolVar(&o.quiet, "noruntimestats", false, "do not print the GC / heap / goroutine report at the end of the run")
+}
+
+// start begins the requested profiles and the runtime monitor; stop ends them, writes the profiles
+// and prints the runtime report to w
+func (o *profileOptions) start(w io.Writer) (stop func() error, err error) {
+	var closers []func() error
+	// on a failed start, undo what was started
+	defer func() {
+		if err != nil {
+			for i := len(closers) - 1; i >= 0; i-- {
+				closers[i]()
+			}
+		}
+	}()
+
+	if o.cpu != "" {
+		f, err := os.Create(o.cpu)
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		closers = append(closers, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+	if o.trace != "" {
+		f, err := os.Create(o.trace)
+		if err != nil {
+			return nil, err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		closers = append(closers, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+	if o.block != "" {
+		runtime.SetBlockProfileRate(1)
+		closers = append(closers, func() error {
+			defer runtime.SetBlockProfileRate(0)
+			return writeProfile("block", o.block)
+		})
+	}
+	if o.mem != "" {
+		closers = append(closers, func() error {
+			runtime.GC() // up-to-date statistics of live objects
+			return writeProfile("heap", o.mem)
+		})
+	}
+	// the report is the monitor's only output, so -noruntimestats does not start it
+	var mon *runtimeMonitor
+	if !o.quiet {
+		mon = startRuntimeMonitor(time.Millisecond, time.Second)
+	}
+
+	return func() error {
+		var stats runtimeStats
+		if mon != nil {
+			stats = mon.stop()
+		}
+		var first error
+		for _, c := range closers {
+			if err := c(); err != nil && first == nil {
+				first = err
+			}
+		}
+		if mon != nil {
+			stats.print(w)
+		}
+		return first
+	}, nil
+}
+
+func writeProfile(name, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runtimeStats summarizes a run: GC activity, the peak live heap and the most goroutines alive at once
+// (heap and goroutines are sampled, so short spikes between samples can be missed)
+type runtimeStats struct {
+	elapsed       time.Duration
+	numGC         uint32
+	pauseTotal    time.Duration
+	pauseMax      time.Duration
+	peakHeap      uint64
+	maxGoroutines uint64
+	interval      time.Duration // first sampling interval
+	lastInterval  time.Duration // sampling interval at the end of the run (the monitor backs off)
+}
+
+func (s *runtimeStats) print(w io.Writer) {
+	sampling := s.interval.String()
+	if s.lastInterval > s.interval {
+		sampling += ", backing off to " + s.lastInterval.String()
+	}
+	fmt.Fprintf(w, "Runtime: %v wall, %d GC cycles, GC pauses total %v (max %v), peak heap %.1f MiB, max goroutines %d (sampled every %s)\n",
+		s.elapsed.Round(time.Millisecond), s.numGC, s.pauseTotal, s.pauseMax, float64(s.peakHeap)/(1<<20), s.maxGoroutines, sampling)
+}
+
+// runtimeMonitor samples the live heap and the goroutine count until stopped
+type runtimeMonitor struct {
+	start    time.Time
+	gcBefore runtime.MemStats
+	done     chan struct{}
+	result   chan runtimeStats
+}
+
+// samplesPerInterval is how many samples the monitor takes before doubling its interval
+const samplesPerInterval = 100
+
+// startRuntimeMonitor samples every interval at first, and doubles the interval every samplesPerInterval
+// samples up to maxInterval: short runs are sampled finely, long ones (serve) cost next to nothing
+func startRuntimeMonitor(interval, maxInterval time.Duration) *runtimeMonitor {
+	m := &runtimeMonitor{start: time.Now(), done: make(chan struct{}), result: make(chan runtimeStats, 1)}
+	runtime.ReadMemStats(&m.gcBefore)
+	go func() {
+		// runtime/metrics reads are cheap and, unlike ReadMemStats, do not stop the world
+		samples := []metrics.Sample{
+			{Name: "/memory/classes/heap/objects:bytes"},
+			{Name: "/sched/goroutines:goroutines"},
+		}
+		stats := runtimeStats{interval: interval, lastInterval: interval}
+		sample := func() {
+			metrics.Read(samples)
+			if samples[0].Value.Kind() == metrics.KindUint64 {
+				stats.peakHeap = max(stats.peakHeap, samples[0].Value.Uint64())
+			}
+			if samples[1].Value.Kind() == metrics.KindUint64 {
+				stats.maxGoroutines = max(stats.maxGoroutines, samples[1].Value.Uint64())
+			}
+		}
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		for n := 1; ; n++ {
+			sample()
+			select {
+			case <-timer.C:
+				if n%samplesPerInterval == 0 && stats.lastInterval < maxInterval {
+					stats.lastInterval = min(2*stats.lastInterval, maxInterval)
+				}
+				timer.Reset(stats.lastInterval)
+			case <-m.done:
+				sample()
+				m.result <- stats
+				return
+			}
+		}
+	}()
+	return m
+}
+
+func (m *runtimeMonitor) stop() runtimeStats {
+	close(m.done)
+	stats := <-m.result
+	stats.elapsed = time.Since(m.start)
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	stats.numGC = after.NumGC - m.gcBefore.NumGC
+	stats.pauseTotal = time.Duration(after.PauseTotalNs - m.gcBefore.PauseTotalNs)
+	// PauseNs is a ring of the last 256 pauses
+	for i := uint32(0); i < min(stats.numGC, uint32(len(after.PauseNs))); i++ {
+		p := time.Duration(after.PauseNs[(after.NumGC-1-i)%uint32(len(after.PauseNs))])
+		stats.pauseMax = max(stats.pauseMax, p)
+	}
+	return stats
+}
@@ -1,5 +1,7 @@
 package main
 
+import "context"
+
 // Sentry records “seed i reached vertex v at distance d”
 type Sentry struct {
 	Seed, Dist int
@@ -8,7 +10,16 @@ type Sentry struct {
 /* Without R!! */
 // SequentialBFSWithS runs a plain multi‐source BFS from seeds that returns the same D and S as ClusterBFS
 func SequentialBFS(G [][]int, seeds []int) (D []int, S [][]Sentry) {
+	D, S, _ = SequentialBFSContext(context.Background(), G, seeds) // never cancelled
+	return D, S
+}
+
+// SequentialBFSContext is SequentialBFS with cancellation, checked every 4096 queue items: when ctx is done
+// it returns what it has with a *PartialResultError whose Completed distances are final
+// (S entries at larger distances may be missing)
+func SequentialBFSContext(ctx context.Context, G [][]int, seeds []int) (D []int, S [][]Sentry, err error) {
 	n := len(G)
+	done := ctx.Done()
 	INF := 1_000_000_000
 
 	// Initialize S and D
@@ -47,6 +58,10 @@ func SequentialBFS(G [][]int, seeds []int) (D []int, S [][]Sentry) {
 	for head := 0; head < len(queue); head++ {
 		curr := queue[head]
 		u, si, d := curr.v, curr.si, curr.d
+		// items are popped in distance order, so every distance up to d is complete
+		if head&4095 == 0 && canceled(done) {
+			return D, S, &PartialResultError{Op: "SequentialBFS", Unit: "rounds", Completed: d + 1, Err: ctx.Err()}
+		}
 		nd := d + 1
 		for _, v := range G[u] {
 			// if this seed can reach v shorter than before (new info)
@@ -64,6 +79,68 @@ func SequentialBFS(G [][]int, seeds []int) (D []int, S [][]Sentry) {
 		}
 	}
 
+	return D, S, nil
+}
+
+// SequentialClusterBFS is a sequential reference for ClusterBFS with radius R
+// It runs the same round-synchronous multi-source BFS one vertex at a time and returns exactly the D and
+// S[v][r] bitmasks that ClusterBFS produces: bit i of S[v][r] is set when seed i first reaches v in round D[v]+r,
+// and v stops accepting seeds R rounds after it was first reached (CondFunc).
+// seeds is a batch as passed to ClusterBFS.Init (it stops at the padding or the sentinel n).
+func SequentialClusterBFS(G [][]int, seeds []int, R int) (D []uint64, S [][]uint64) {
+	n := len(G)
+	INF := ^uint64(0)
+	D = make([]uint64, n)
+	S = make([][]uint64, n)
+	S0 := make([]uint64, n) // seeds that reached v in earlier rounds
+	S1 := make([]uint64, n) // seeds that reached v up to this round
+	for v := range D {
+		D[v] = INF
+		S[v] = make([]uint64, R)
+	}
+
+	// Same seed handling as ClusterBFS.Init
+	var frontier []int
+	for i, v := range seeds {
+		if (i != 0 && v == seeds[0]) || v >= n {
+			break
+		}
+		S1[v] = 1 << uint(i)
+		frontier = append(frontier, v)
+	}
+
+	inNext := make([]bool, n)
+	for round := uint64(0); len(frontier) > 0; round++ {
+		// FrontierFunc: record the seeds that are new at v in this round
+		for _, v := range frontier {
+			difference := S1[v] &^ S0[v]
+			if D[v] == INF {
+				D[v] = round
+			}
+			S[v][round-D[v]] = difference
+			S0[v] |= difference
+		}
+		// EdgeFunc + CondFunc: push everything u knows to neighbors still inside their window
+		var next []int
+		for _, u := range frontier {
+			for _, v := range G[u] {
+				if D[v] != INF && round+1-D[v] >= uint64(R) {
+					continue
+				}
+				if S0[u]&^S1[v] != 0 {
+					S1[v] |= S0[u]
+					if !inNext[v] {
+						inNext[v] = true
+						next = append(next, v)
+					}
+				}
+			}
+		}
+		for _, v := range next {
+			inNext[v] = false
+		}
+		frontier = next
+	}
 	return D, S
 }
 
@@ -3,17 +3,17 @@ package main
 import (
 	"cluster_bfs_go/graphutils"
 	"flag"
-	"fmt"
 	"os"
 	"testing"
 )
 
 // Input flags
 var (
-	path = flag.String("f", "", "path to graph.bin")
-	k    = flag.Int("k", 8, "seeds per batch")
-	ns   = flag.Int("ns", 1, "number of seed batches")
-	r    = flag.Int("r", 4, "BFS radius for verify")
+	path     = flag.String("f", "", "path to graph.bin (default: a generated Kronecker graph)")
+	k        = flag.Int("k", 8, "seeds per batch")
+	ns       = flag.Int("ns", 1, "number of seed batches")
+	r        = flag.Int("r", 4, "BFS radius for verify")
+	strategy = flag.String("strategy", "1hop", "seed selection strategy (see graphutils.SeedStrategyNames)")
 )
 
 func TestMain(m *testing.M) {
@@ -23,27 +23,31 @@ func TestMain(m *testing.M) {
 
 // *testing.T: the mechanism by which the test function communicates success or failure back to the Go test
 func TestSequentialMatchesCluster(t *testing.T) {
+	// Read the graph (or generate one when no file is given) and select seeds
+	var offs64 []uint64
+	var edges32 []uint32
 	if *path == "" {
-		fmt.Fprintln(os.Stderr, "Usage: -f graph.bin [-k #] [-ns #] [-r #]")
-		os.Exit(1)
-	}
-
-	// Read the graph and select seeds
-	offs64, edges32, err := graphutils.ReadGraphFromBin(*path)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading graph: %v\n", err)
-		os.Exit(1)
+		offs64, edges32 = graphutils.Kronecker(10, 8, 1)
+	} else {
+		var err error
+		offs64, edges32, err = graphutils.ReadGraphFromBin(*path)
+		if err != nil {
+			t.Fatalf("Error loading graph: %v", err)
+		}
 	}
 	G := graphutils.BuildAdjFromCSR(offs64, edges32)
 	GT := graphutils.TransposeAdj(G)
 
 	// Select seeds (One batch is enough)
-	seeds := make([][]int, *ns)
-	for i := range seeds {
-		seeds[i] = make([]int, *k)
+	selector, err := graphutils.NewSeedSelector(*strategy, graphutils.SeedOptions{Seed: 1, R: *r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, err := selector.Select(G, *ns, *k)
+	if err != nil {
+		t.Fatal(err)
 	}
-	graphutils.SelectSeeds1(G, seeds)
-	firstBatch := seeds[0] // One batch is enough for testing!
+	firstBatch := sb.Batches[0] // One batch is enough for testing!
 
 	cbfs := &ClusterBFS{G: G, GT: GT, R: *r}
 	goSeeds := cbfs.Init(firstBatch)
@@ -51,6 +55,10 @@ func TestSequentialMatchesCluster(t *testing.T) {
 
 	Dseq, _ := SequentialBFS(G, firstBatch)
 	for v := range G {
+		// both report unreachable vertices with their own INF
+		if Dseq[v] == 1_000_000_000 && cbfs.D[v] == cbfs.INF {
+			continue
+		}
 		if Dseq[v] != int(cbfs.D[v]) {
 			t.Fatalf("v=%d: seq=%d vs cluster=%d", v, Dseq[v], cbfs.D[v])
 		}
@@ -60,3 +68,30 @@ func TestSequentialMatchesCluster(t *testing.T) {
 /*
 go test -v -run TestSequentialMatchesCluster -args -f data/graphs/Epinions1_sym.bin -k 3 -ns 1 -r 4
 */
+
+// ClusterBFS must produce exactly the labels of the sequential reference
+func TestSequentialLabelsMatchCluster(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.Kronecker(9, 8, 2))
+	for _, R := range []int{1, 2, 3, 4} {
+		selector, _ := graphutils.NewSeedSelector("2hop-heap", graphutils.SeedOptions{Seed: uint64(R)})
+		sb, err := selector.Select(G, 2, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, batch := range sb.Used() {
+			cbfs := &ClusterBFS{G: G, GT: G, R: R}
+			cbfs.RunCBFS(cbfs.Init(batch))
+			D, S := SequentialClusterBFS(G, batch, R)
+			for v := range G {
+				if D[v] != cbfs.D[v] {
+					t.Fatalf("R=%d v=%d: D seq=%d cluster=%d", R, v, D[v], cbfs.D[v])
+				}
+				for r := 0; r < R; r++ {
+					if S[v][r] != cbfs.S[v][r] {
+						t.Fatalf("R=%d v=%d: S[%d] seq=%b cluster=%b", R, v, r, S[v][r], cbfs.S[v][r])
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"cluster_bfs_go/client"
+	"cluster_bfs_go/graphutils"
+	"cluster_bfs_go/parlay_go"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// distanceServer answers HTTP queries on a loaded index; vertex IDs in requests and responses are those of the input graph
+type distanceServer struct {
+	oracle   *Oracle
+	G, GT    [][]int // the graph the index was built on, for /neighbors-within and ClusterBFS jobs
+	toInput  []int   // toInput[l]: input ID of vertex l of G
+	meta     client.Metadata
+	start    time.Time
+	maxBatch int
+	jobs     chan struct{} // one ClusterBFS job at a time: each one already uses every core
+	// bibfs is the search budget (visited vertices) of the fallback BiBFS for estimates the labels
+	// do not certify exact; 0 turns the fallback off
+	bibfs int
+	// jobTimeout caps the ClusterBFS run of a gRPC job (0: no limit besides the client's deadline)
+	jobTimeout time.Duration
+	metrics    *serverMetrics
+}
+
+// newDistanceServer checks that lg is the graph the index was built on (same -lcc / -order) and prepares the server
+// The graph must be symmetric: QueryExact's bounds (and so "exact" answers) only hold for undirected distances
+func newDistanceServer(oracle *Oracle, lg *loadedGraph, meta client.Metadata, maxBatch int) (*distanceServer, error) {
+	if len(oracle.D) == 0 || len(oracle.D[0]) != len(lg.G) {
+		return nil, fmt.Errorf("graph has %d vertices but the index labels a different graph", len(lg.G))
+	}
+	if !graphutils.IsSymmetric(lg.G) {
+		return nil, errNotSymmetric
+	}
+	if oracle.ToLocal == nil && !lg.Identity || oracle.ToLocal != nil && !slices.Equal(oracle.ToLocal, lg.ToLocal) {
+		return nil, fmt.Errorf("graph does not match the index: load it with the -lcc / -order used by build-index")
+	}
+	toInput := make([]int, len(lg.G))
+	for v, l := range lg.ToLocal {
+		if l >= 0 {
+			toInput[l] = v
+		}
+	}
+	meta.N, meta.M = len(lg.G), lg.M()
+	meta.R, meta.Batches, meta.K = oracle.R, len(oracle.Seeds), len(oracle.Seeds[0])
+	return &distanceServer{
+		oracle: oracle, G: lg.G, GT: lg.GT, toInput: toInput, meta: meta,
+		start: time.Now(), maxBatch: maxBatch, jobs: make(chan struct{}, 1), metrics: newServerMetrics(oracle),
+	}, nil
+}
+
+func (s *distanceServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /metadata", s.handleMetadata)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /distance", s.timed("distance", s.handleDistance))
+	mux.HandleFunc("POST /distance/batch", s.timed("distance_batch", s.handleBatch))
+	mux.HandleFunc("GET /neighbors-within", s.timed("neighbors_within", s.handleNeighbors))
+	return mux
+}
+
+// timed records the latency of every request to h under endpoint
+func (s *distanceServer) timed(endpoint string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		h(w, r)
+		s.metrics.observeLatency(endpoint, time.Since(start))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, client.ErrorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// intParam reads a required non-negative integer query parameter
+func intParam(r *http.Request, name string) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return 0, fmt.Errorf("missing parameter %q", name)
+	}
+	x, err := strconv.Atoi(s)
+	if err != nil || x < 0 {
+		return 0, fmt.Errorf("parameter %q: want a non-negative integer, got %q", name, s)
+	}
+	return x, nil
+}
+
+// distance maps input IDs to the labels and queries the oracle, falling back to a BiBFS when the estimate is
+// not certified exact; nil means unreachable or not in the index. Every answer is counted in the metrics
+func (s *distanceServer) distance(u, v int) (d *uint64, exact bool) {
+	lu, okU := s.oracle.Local(u)
+	lv, okV := s.oracle.Local(v)
+	if !okU || !okV {
+		s.metrics.countAnswer(answerUnreachable)
+		return nil, false
+	}
+	est, exact := s.oracle.QueryExact(lu, lv)
+	return s.resolve(lu, lv, est, exact)
+}
+
+// distances is distance for a batch of pairs, with the index queries evaluated by Oracle.QueryBatch
+// and the BiBFS fallbacks run in parallel
+func (s *distanceServer) distances(pairs [][2]int) (d []*uint64, exact []bool) {
+	d, exact = make([]*uint64, len(pairs)), make([]bool, len(pairs))
+	// 1) Map the pairs to the labels' IDs; those with a vertex outside the index stay unreachable
+	var local [][2]int
+	var at []int // at[j]: index in pairs of local[j]
+	for i, p := range pairs {
+		lu, okU := s.oracle.Local(p[0])
+		lv, okV := s.oracle.Local(p[1])
+		if !okU || !okV {
+			s.metrics.countAnswer(answerUnreachable)
+			continue
+		}
+		local = append(local, [2]int{lu, lv})
+		at = append(at, i)
+	}
+
+	// 2) Query the index, then fall back pair by pair
+	ests, certified := s.oracle.QueryBatch(local)
+	parlay_go.ParallelFor(len(local), func(j int) {
+		d[at[j]], exact[at[j]] = s.resolve(local[j][0], local[j][1], ests[j], certified[j])
+	})
+	return d, exact
+}
+
+// resolve turns the index answer est for (lu, lv) into the served one: certified estimates are returned as is,
+// the others go through the BiBFS fallback first. The answer kind is counted in the metrics
+func (s *distanceServer) resolve(lu, lv int, est uint64, exact bool) (*uint64, bool) {
+	kind := answerUnreachable
+	defer func() { s.metrics.countAnswer(kind) }()
+	if exact {
+		kind = answerExact
+		return &est, true
+	}
+	if s.bibfs > 0 {
+		if bd, ok := graphutils.BiBFS(s.G, s.GT, lu, lv, s.bibfs); ok {
+			kind = answerBiBFS
+			found := uint64(bd)
+			return &found, true
+		}
+	}
+	if est == s.oracle.INF {
+		return nil, false
+	}
+	kind = answerBound
+	return &est, false
+}
+
+func (s *distanceServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, client.HealthResponse{Status: "ok"})
+}
+
+func (s *distanceServer) handleMetadata(w http.ResponseWriter, r *http.Request) {
+	meta := s.meta
+	meta.UptimeSeconds = time.Since(s.start).Seconds()
+	writeJSON(w, http.StatusOK, meta)
+}
+
+func (s *distanceServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.writeTo(w)
+}
+
+func (s *distanceServer) handleDistance(w http.ResponseWriter, r *http.Request) {
+	u, err := intParam(r, "u")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	v, err := intParam(r, "v")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	for _, x := range []int{u, v} {
+		if _, ok := s.oracle.Local(x); !ok {
+			writeError(w, http.StatusNotFound, "vertex %d is not in the index", x)
+			return
+		}
+	}
+	d, exact := s.distance(u, v)
+	writeJSON(w, http.StatusOK, client.DistanceResponse{U: u, V: v, Distance: d, Exact: exact})
+}
+
+func (s *distanceServer) handleBatch(w http.ResponseWriter, r *http.Request) {
+	// 32 bytes per pair is plenty for JSON like [123456789,987654321],
+	r.Body = http.MaxBytesReader(w, r.Body, int64(s.maxBatch)*32+1024)
+	var req client.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body over %d bytes", tooLarge.Limit)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "bad request body: %v", err)
+		return
+	}
+	if len(req.Pairs) > s.maxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, "%d pairs in one request, the limit is %d", len(req.Pairs), s.maxBatch)
+		return
+	}
+	var resp client.BatchResponse
+	resp.Distances, resp.Exact = s.distances(req.Pairs)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *distanceServer) handleNeighbors(w http.ResponseWriter, r *http.Request) {
+	v, err := intParam(r, "v")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	radius, err := intParam(r, "r")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if radius > s.meta.MaxRadius {
+		writeError(w, http.StatusBadRequest, "r=%d is over the limit of %d", radius, s.meta.MaxRadius)
+		return
+	}
+	lv, ok := s.oracle.Local(v)
+	if !ok {
+		writeError(w, http.StatusNotFound, "vertex %d is not in the index", v)
+		return
+	}
+	// level-synchronous BFS from lv, stopping after radius levels
+	dist := map[int]int{lv: 0}
+	resp := client.NeighborsResponse{V: v, R: radius, Neighbors: []client.Neighbor{{V: v, D: 0}}}
+	frontier := []int{lv}
+	for d := 1; d <= radius && len(frontier) > 0; d++ {
+		var next []int
+		for _, u := range frontier {
+			for _, x := range s.G[u] {
+				if _, seen := dist[x]; !seen {
+					dist[x] = d
+					next = append(next, x)
+					resp.Neighbors = append(resp.Neighbors, client.Neighbor{V: s.toInput[x], D: d})
+				}
+			}
+		}
+		frontier = next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// checkLoopback rejects listen addresses that are reachable from other machines
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return usageErrorf("-addr %q: %v", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return usageErrorf("-addr %q: the server only listens on loopback addresses (127.0.0.1, ::1 or localhost)", addr)
+}
+
+// serve: load the graph and a saved index and answer distance queries over HTTP on localhost
+func serveCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	g.register(fs)
+	g.registerSeed(fs)
+	index := fs.String("index", "", "index file written by build-index (required)")
+	addr := fs.String("addr", "127.0.0.1:8080", "listen address (loopback only)")
+	grpcAddr := fs.String("grpc", "", "also serve the gRPC API (cbfspb/cbfs.proto) on this address (loopback only)")
+	maxBatch := fs.Int("maxbatch", 100000, "most pairs accepted by one POST /distance/batch")
+	maxRadius := fs.Int("maxr", 3, "largest r accepted by /neighbors-within and gRPC jobs")
+	bibfs := fs.Int("bibfs", 10000, "search budget (visited vertices) of the BiBFS run when the index cannot certify a distance exact (0: off)")
+	jobTimeout := fs.Duration("jobtimeout", time.Minute, "longest ClusterBFS run of one gRPC job (0: no limit)")
+	return func(ctx context.Context) error {
+		if *index == "" {
+			return usageErrorf("missing -index file")
+		}
+		if *maxBatch < 1 || *maxRadius < 0 || *bibfs < 0 || *jobTimeout < 0 {
+			return usageErrorf("-maxbatch must be positive, -maxr, -bibfs and -jobtimeout non-negative")
+		}
+		if err := checkLoopback(*addr); err != nil {
+			return err
+		}
+		if *grpcAddr != "" {
+			if err := checkLoopback(*grpcAddr); err != nil {
+				return err
+			}
+		}
+		lg, err := g.load()
+		if err != nil {
+			return err
+		}
+		oracle, err := LoadOracle(*index)
+		if err != nil {
+			return err
+		}
+		srv, err := newDistanceServer(oracle, lg, client.Metadata{Graph: g.path, Index: *index, MaxRadius: *maxRadius}, *maxBatch)
+		if err != nil {
+			return err
+		}
+		srv.bibfs, srv.jobTimeout = *bibfs, *jobTimeout
+
+		ln, err := net.Listen("tcp", *addr)
+		if err != nil {
+			return err
+		}
+		httpSrv := &http.Server{Handler: srv.handler(), ReadHeaderTimeout: 10 * time.Second}
+		fmt.Printf("Serving %s (n=%d, R=%d, %d batches) on http://%s\n", *index, srv.meta.N, srv.meta.R, srv.meta.Batches, ln.Addr())
+
+		errc := make(chan error, 2)
+		go func() { errc <- httpSrv.Serve(ln) }()
+		if *grpcAddr != "" {
+			gln, err := net.Listen("tcp", *grpcAddr)
+			if err != nil {
+				httpSrv.Close()
+				return err
+			}
+			gs := newGRPCServer(srv)
+			defer gs.GracefulStop()
+			go func() { errc <- gs.Serve(gln) }()
+			fmt.Printf("Serving gRPC on %s\n", gln.Addr())
+		}
+
+		// run until SIGINT / SIGTERM (or -timeout), then let in-flight requests finish
+		select {
+		case err := <-errc:
+			httpSrv.Close()
+			return err
+		case <-ctx.Done():
+		}
+		fmt.Println("Shutting down")
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelShutdown()
+		return httpSrv.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"cluster_bfs_go/client"
+	"cluster_bfs_go/graphutils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// The client against a server on a 6x6 grid with one vertex dropped in front (as -lcc would)
+func TestServeWithClient(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.Grid2D(6, 6))
+	oracle := BuildOracle(G, G, [][]int{{0, 1, 6}, {35, 34, 29}}, 3)
+	toLocal := make([]int, len(G)+1)
+	for v := range toLocal {
+		toLocal[v] = v - 1
+	}
+	oracle.ToLocal = toLocal
+	lg := &loadedGraph{G: G, GT: G, ToLocal: toLocal}
+	srv, err := newDistanceServer(oracle, lg, client.Metadata{Index: "grid.idx", MaxRadius: 2}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+	c := client.New(ts.URL)
+	ctx := context.Background()
+
+	if err := c.Health(ctx); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := c.Metadata(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.N != 36 || meta.R != 3 || meta.Batches != 2 || meta.K != 3 || meta.Index != "grid.idx" {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+
+	// input vertex 1 is grid vertex 0, input 36 is grid vertex 35: opposite corners, 10 hops apart
+	d, err := c.Distance(ctx, 1, 36)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == nil || *d != oracle.Query(0, 35) || *d < 10 {
+		t.Fatalf("Distance(1, 36) = %v, want the oracle's estimate %d", d, oracle.Query(0, 35))
+	}
+	var apiErr *client.Error
+	if _, err := c.Distance(ctx, 0, 1); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("dropped vertex: got %v, want a 404", err)
+	}
+
+	ds, err := c.Distances(ctx, [][2]int{{1, 36}, {3, 3}, {0, 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds[0] == nil || *ds[0] != *d || ds[1] == nil || *ds[1] != 0 || ds[2] != nil {
+		t.Fatalf("unexpected batch answer %v", ds)
+	}
+	if _, err := c.Distances(ctx, make([][2]int, 5)); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized batch: got %v, want a 413", err)
+	}
+
+	// grid vertex 0 (input 1) has 2 neighbors at distance 1 and 3 at distance 2
+	nbrs, err := c.NeighborsWithin(ctx, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := map[int]int{}
+	for _, nb := range nbrs {
+		count[nb.D]++
+		if want := abs(nb.V-1)%6 + abs(nb.V-1)/6; nb.D != want {
+			t.Fatalf("neighbor %d at distance %d, want %d", nb.V, nb.D, want)
+		}
+	}
+	if count[0] != 1 || count[1] != 2 || count[2] != 3 {
+		t.Fatalf("unexpected neighborhood %v", nbrs)
+	}
+	if _, err := c.NeighborsWithin(ctx, 1, 3); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("radius over -maxr: got %v, want a 400", err)
+	}
+
+	// a graph loaded without the index's -lcc / -order is rejected
+	if _, err := newDistanceServer(oracle, &loadedGraph{G: G, GT: G, ToLocal: toLocal[1:], Identity: true}, client.Metadata{}, 4); err == nil {
+		t.Fatal("expected an error for a graph that does not match the index")
+	}
+	// so is a directed graph, where the exactness certificate does not hold
+	directed := graphutils.BuildAdjFromCSR(graphutils.PathGraph(len(G)))
+	directed[0] = nil
+	if _, err := newDistanceServer(oracle, &loadedGraph{G: directed, GT: graphutils.TransposeAdj(directed), ToLocal: toLocal}, client.Metadata{}, 4); err == nil {
+		t.Fatal("expected an error for a non-symmetric graph")
+	}
+}
+
+func TestCheckLoopback(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:0":    true,
+		"[::1]:9000":     true,
+		"0.0.0.0:8080":   false,
+		":8080":          false,
+		"10.0.0.1:80":    false,
+		"127.0.0.1":      false,
+	} {
+		if err := checkLoopback(addr); (err == nil) != ok {
+			t.Errorf("checkLoopback(%q) = %v", addr, err)
+		}
+	}
+}
+
+// Estimates the labels do not certify are bounds, unless the BiBFS fallback finds the distance
+func TestServeBiBFSFallback(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.PathGraph(12))
+	oracle := BuildOracle(G, G, [][]int{{5, 4, 6}}, 3)
+	toLocal := make([]int, len(G))
+	for v := range toLocal {
+		toLocal[v] = v
+	}
+	srv, err := newDistanceServer(oracle, &loadedGraph{G: G, GT: G, ToLocal: toLocal, Identity: true}, client.Metadata{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+	get := func(u, v int) client.DistanceResponse {
+		t.Helper()
+		resp, err := http.Get(fmt.Sprintf("%s/distance?u=%d&v=%d", ts.URL, u, v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var d client.DistanceResponse
+		if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	// (5, 9) is certified by seed 5; (0, 11) is not, so it is only a bound without the fallback
+	for _, tc := range []struct {
+		u, v  int
+		d     uint64
+		exact bool
+	}{{5, 9, 4, true}, {0, 11, 11, false}} {
+		if d := get(tc.u, tc.v); d.Distance == nil || *d.Distance != tc.d || d.Exact != tc.exact {
+			t.Fatalf("Distance(%d, %d) = %+v, want %d, exact %v", tc.u, tc.v, d, tc.d, tc.exact)
+		}
+	}
+	srv.bibfs = 100
+	if d := get(0, 11); d.Distance == nil || *d.Distance != 11 || !d.Exact {
+		t.Fatalf("Distance(0, 11) with the fallback = %+v, want 11, exact", d)
+	}
+}
+
+// Every answer kind and the index gauges show up on /metrics
+func TestServeMetrics(t *testing.T) {
+	G := graphutils.BuildAdjFromCSR(graphutils.PathGraph(12))
+	oracle := BuildOracle(G, G, [][]int{{5, 4, 6}}, 3)
+	toLocal := make([]int, len(G))
+	for v := range toLocal {
+		toLocal[v] = v
+	}
+	srv, err := newDistanceServer(oracle, &loadedGraph{G: G, GT: G, ToLocal: toLocal, Identity: true}, client.Metadata{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+	c := client.New(ts.URL)
+	ctx := context.Background()
+
+	// (5, 9) is certified by seed 5; (0, 11) is not, so it is a bound without the fallback and exact with it
+	if _, err := c.Distances(ctx, [][2]int{{5, 9}, {0, 11}, {0, 12}}); err != nil {
+		t.Fatal(err)
+	}
+	srv.bibfs = 100
+	if _, err := c.Distance(ctx, 0, 11); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`cbfs_query_answers_total{answer="exact"} 1`,
+		`cbfs_query_answers_total{answer="bibfs"} 1`,
+		`cbfs_query_answers_total{answer="bound"} 1`,
+		`cbfs_query_answers_total{answer="unreachable"} 1`,
+		`cbfs_query_duration_seconds_count{endpoint="distance"} 1`,
+		`cbfs_query_duration_seconds_count{endpoint="distance_batch"} 1`,
+		`cbfs_query_duration_seconds_bucket{endpoint="distance",le="+Inf"} 1`,
+		`cbfs_index_build_batch_duration_seconds_count 1`,
+		`cbfs_index_label_bytes{array="D"} 96`,
+		`cbfs_index_label_bytes{array="S"} 576`,
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Errorf("/metrics is missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// catalogEntry is one dataset of a sweep catalog
+type catalogEntry struct {
+	Name string // abbreviation used in the table (EP, SLDT, ...)
+	Path string
+}
+
+// readCatalog parses a sweep catalog (see data/catalog.txt)
+/*
+Data format: one dataset per line, blank lines and lines starting with # are ignored
+<abbreviation> <path>
+*/
+func readCatalog(path string) ([]catalogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var entries []catalogEntry
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want \"<abbreviation> <path>\"", path, line)
+		}
+		entries = append(entries, catalogEntry{Name: fields[0], Path: fields[1]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: no datasets", path)
+	}
+	return entries, nil
+}
+
+// parseIntList parses a comma-separated list of positive integers such as "2,3,4"
+func parseIntList(flagName, s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		x, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || x < 1 {
+			return nil, usageErrorf("-%s: bad value %q (want a comma-separated list of positive integers)", flagName, f)
+		}
+		out = append(out, x)
+	}
+	return out, nil
+}
+
+// sweepRow is one configuration of the sweep; nil results were not run
+type sweepRow struct {
+	graph       string
+	n, m        int
+	R, k, cores int
+	cbfs, seq   *BenchResult
+}
+
+// sweep: bench every catalog dataset over the R × k × cores grid, with ClusterBFS and the sequential BFS
+func sweepCommand(fs *flag.FlagSet) func(ctx context.Context) error {
+	var g graphOptions
+	var s seedOptions
+	g.registerPreprocessing(fs)
+	s.registerSelection(fs)
+	fs.IntVar(&s.ns, "ns", 10, "number of seed batches")
+	catalog := fs.String("catalog", "data/catalog.txt", "catalog of datasets (\"<abbreviation> <path>\" lines)")
+	only := fs.String("graphs", "", "comma-separated abbreviations to run (default: all of the catalog)")
+	rs := fs.String("r", "2", "comma-separated BFS radii")
+	ks := fs.String("k", "64", "comma-separated batch sizes")
+	cs := fs.String("c", strconv.Itoa(runtime.NumCPU()), "comma-separated core counts (GOMAXPROCS)")
+	t := fs.Int("t", 3, "iterations per configuration")
+	algos := fs.String("algos", "cbfs,seq", "algorithms to run: cbfs, seq or both")
+	out := fs.String("out", "", "append every result to this file (CSV for .csv files, JSON lines otherwise)")
+	format := fs.String("format", "", "result format for -out: csv or json (default: from the file extension)")
+	return func(ctx context.Context) error {
+		Rs, err := parseIntList("r", *rs)
+		if err != nil {
+			return err
+		}
+		Ks, err := parseIntList("k", *ks)
+		if err != nil {
+			return err
+		}
+		Cs, err := parseIntList("c", *cs)
+		if err != nil {
+			return err
+		}
+		if *t < 1 {
+			return usageErrorf("-t must be positive")
+		}
+		if s.file != "" {
+			// a seed file fixes the batch size, so it cannot follow the -k grid
+			return usageErrorf("sweep selects seeds for every -k: -seeds is not supported (use bench)")
+		}
+		runCBFS, runSeq := false, false
+		for _, a := range strings.Split(*algos, ",") {
+			switch strings.TrimSpace(a) {
+			case "cbfs":
+				runCBFS = true
+			case "seq":
+				runSeq = true
+			default:
+				return usageErrorf("-algos: unknown algorithm %q (want cbfs or seq)", a)
+			}
+		}
+		if _, err := resultFormat(*out, *format); err != nil {
+			return err
+		}
+		entries, err := readCatalog(*catalog)
+		if err != nil {
+			return err
+		}
+		if *only != "" {
+			keep := map[string]bool{}
+			for _, name := range strings.Split(*only, ",") {
+				keep[strings.TrimSpace(name)] = true
+			}
+			filtered := entries[:0]
+			for _, e := range entries {
+				if keep[e.Name] {
+					filtered = append(filtered, e)
+				}
+			}
+			entries = filtered
+		}
+
+		var rows []sweepRow
+		// missing files and configurations without seeds are skipped, not fatal
+		var skipped []string
+		skip := func(what string, err error) {
+			fmt.Printf("Skipping %s: %v\n", what, err)
+			skipped = append(skipped, fmt.Sprintf("%s (%v)", what, err))
+		}
+		g.seed = s.rngSeed()
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0)) // the core counts of the grid are set below
+		for _, e := range entries {
+			if _, err := os.Stat(e.Path); err != nil {
+				skip(e.Name, err)
+				continue
+			}
+			fmt.Printf("=== %s (%s)\n", e.Name, e.Path)
+			g.path = e.Path
+			lg, err := g.load()
+			if err != nil {
+				skip(e.Name, err)
+				continue
+			}
+			n, m := len(lg.G), lg.M()
+			for _, k := range Ks {
+				for _, R := range Rs {
+					// the same seeds for every core count and both algorithms
+					s.k = k
+					seeds, err := s.batches(lg, R)
+					if err != nil {
+						skip(fmt.Sprintf("%s k=%d R=%d", e.Name, k, R), err)
+						continue
+					}
+					for _, c := range Cs {
+						runtime.GOMAXPROCS(c)
+						row := sweepRow{graph: e.Name, n: n, m: m, R: R, k: k, cores: c}
+						for _, seq := range []bool{false, true} {
+							if (seq && !runSeq) || (!seq && !runCBFS) {
+								continue
+							}
+							res, err := singleBatchTest(ctx, seeds, lg.G, lg.GT, *t, false, R, seq)
+							if err != nil {
+								return fmt.Errorf("%s: %w", e.Name, err)
+							}
+							res.Graph, res.M = e.Name, m
+							if seq {
+								row.seq = res
+							} else {
+								row.cbfs = res
+							}
+							if *out != "" {
+								if err := AppendBenchResults(*out, *format, res); err != nil {
+									return err
+								}
+							}
+						}
+						rows = append(rows, row)
+					}
+				}
+			}
+		}
+
+		printSweepTable(rows)
+		if len(skipped) > 0 {
+			fmt.Printf("Skipped:\n  %s\n", strings.Join(skipped, "\n  "))
+		}
+		if len(rows) == 0 {
+			return fmt.Errorf("no configuration of the sweep could run")
+		}
+		return nil
+	}
+}
+
+// printSweepTable prints one line per configuration with the median times and the ClusterBFS speedup
+func printSweepTable(rows []sweepRow) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "graph\tn\tm\tR\tk\tcores\tcbfs ms\tseq ms\tspeedup\t")
+	ms := func(res *BenchResult) string {
+		if res == nil {
+			return "-"
+		}
+		return strconv.FormatFloat(res.MedianMs, 'f', 3, 64)
+	}
+	for _, row := range rows {
+		speedup := "-"
+		if row.cbfs != nil && row.seq != nil && row.cbfs.MedianMs > 0 {
+			speedup = strconv.FormatFloat(row.seq.MedianMs/row.cbfs.MedianMs, 'f', 2, 64) + "x"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t\n",
+			row.graph, row.n, row.m, row.R, row.k, row.cores, ms(row.cbfs), ms(row.seq), speedup)
+	}
+	w.Flush()
+}